@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dorukozerr/todo-cli/internal/config"
+	"github.com/dorukozerr/todo-cli/internal/fs"
+	"github.com/dorukozerr/todo-cli/internal/types"
+	"github.com/dorukozerr/todo-cli/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var archiveCmd = &cobra.Command{
+	Use:   "archive",
+	Short: "Move completed todos into the archive",
+	Long: `Move completed todos from every group out of the store and into
+<store>.archive, keeping their group. With --older-than only todos
+completed before the cutoff are moved, e.g. --older-than 30d.
+
+Subtasks that are still open stay in the store and move up a level, to
+the closest ancestor that is not archived.
+
+Archived todos are shown with 'todo list --archived' and brought back
+with 'todo unarchive <id>'.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		olderThan, _ := cmd.Flags().GetString("older-than")
+
+		match := func(todo types.Todo) bool { return todo.Completed }
+		if olderThan != "" {
+			age, err := utils.ParseDuration(olderThan)
+			if err != nil {
+				fmt.Printf("%s%v%s\n", config.Red, err, config.Reset)
+				return
+			}
+			match = completedBefore(time.Now().Add(-age))
+		}
+
+		s, err := fs.Open()
+		if err != nil {
+			fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+		defer s.Close()
+
+		archived, moved, err := s.Archive(match)
+		if err != nil {
+			fmt.Printf("%sError archiving todos: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		if len(archived) == 0 {
+			fmt.Printf("%sNo completed todos to archive%s\n", config.Yellow, config.Reset)
+			return
+		}
+
+		fmt.Printf("%sArchived %d todo(s)%s\n", config.Green, len(archived), config.Reset)
+		printMovedSubtasks(moved)
+	},
+}
+
+var unarchiveCmd = &cobra.Command{
+	Use:   "unarchive [todo-id]",
+	Short: "Move a todo from the archive back into the store",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ref := args[0]
+
+		s, err := fs.Open()
+		if err != nil {
+			fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+		defer s.Close()
+
+		todo, archivedNumber, err := s.Unarchive(ref)
+		if errors.Is(err, fs.ErrTodoNotFound) {
+			fmt.Printf("%sNo archived todo with ID '%s'%s\n", config.Red, ref, config.Reset)
+			return
+		}
+		if err != nil {
+			fmt.Printf("%sError unarchiving todo: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		fmt.Printf("%sUnarchived todo [%s%d%s]: %s%s%s\n", config.Green,
+			config.Purple, todo.Number, config.Green,
+			config.Bold, todo.Task, config.Reset)
+		if todo.Number != archivedNumber {
+			fmt.Printf("  %sNumber %d was taken, the todo is now [%d]%s\n", config.Yellow, archivedNumber, todo.Number, config.Reset)
+		}
+	},
+}
+
+// completedBefore matches todos completed before cutoff. Todos completed
+// before completion times were recorded count as old.
+func completedBefore(cutoff time.Time) func(todo types.Todo) bool {
+	return func(todo types.Todo) bool {
+		return todo.Completed && (todo.CompletedAt == nil || todo.CompletedAt.Before(cutoff))
+	}
+}
+
+// applyArchivePolicy archives todos completed more than archive_after_days
+// ago, when that setting is on. It runs after a todo changes status rather
+// than on reads, so listing never writes to the store.
+func applyArchivePolicy(s *fs.Handle) {
+	if userSettings.ArchiveAfterDays == 0 {
+		return
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -userSettings.ArchiveAfterDays)
+	archived, moved, err := s.Archive(completedBefore(cutoff))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: automatic archiving failed: %v\n", err)
+		return
+	}
+
+	if len(archived) > 0 {
+		fmt.Printf("%sArchived %d todo(s) completed more than %d days ago%s\n",
+			config.Cyan, len(archived), userSettings.ArchiveAfterDays, config.Reset)
+		printMovedSubtasks(moved)
+	}
+}
+
+// printMovedSubtasks lists the subtasks that stayed in the store while their
+// parent was archived.
+func printMovedSubtasks(moved []types.Todo) {
+	for _, todo := range moved {
+		fmt.Printf("  %sKept subtask [%d] of an archived todo, moved up a level%s\n",
+			config.Cyan, todo.Number, config.Reset)
+	}
+}
+
+func init() {
+	archiveCmd.Flags().String("older-than", "", "Only archive todos completed longer ago than this, e.g. 30d, 2w or 12h")
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dorukozerr/todo-cli/internal/config"
+	"github.com/dorukozerr/todo-cli/internal/fs"
+	"github.com/dorukozerr/todo-cli/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Manage store snapshots",
+	Long: `Manage store snapshots:
+- backup list: Show all snapshots of the current store
+- backup create: Take a manual snapshot now
+
+A snapshot of the previous state is taken automatically before a change
+when the last one is more than ten minutes old; 'todo undo' covers the
+changes in between. The newest backup_keep snapshots and one per day for backup_days
+days are kept; manual snapshots are never rotated away.`,
+}
+
+var backupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List snapshots of the current store",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		s, err := fs.Open()
+		if err != nil {
+			fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+		defer s.Close()
+
+		snapshots, err := s.Snapshots()
+		if err != nil {
+			fmt.Printf("%sError listing snapshots: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		if len(snapshots) == 0 {
+			fmt.Printf("%sNo snapshots found for %s%s\n", config.Yellow, s.Path(), config.Reset)
+			return
+		}
+
+		fmt.Printf("\n%sSnapshots of %s (%d total):%s\n", config.Blue+config.Bold, s.Path(), len(snapshots), config.Reset)
+		fmt.Println("========================================")
+		for _, snapshot := range snapshots {
+			kind := "auto"
+			if snapshot.Manual {
+				kind = "manual"
+			}
+			fmt.Printf("%s%s%s  %s  %s%-6s%s %d bytes\n",
+				config.Purple, snapshot.ID, config.Reset,
+				utils.FormatDateTime(snapshot.Time, userSettings.DateFormat),
+				config.Cyan, kind, config.Reset,
+				snapshot.Size)
+		}
+	},
+}
+
+var backupCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Take a manual snapshot of the current store",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		s, err := fs.Open()
+		if err != nil {
+			fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+		defer s.Close()
+
+		snapshot, err := s.CreateSnapshot()
+		if err != nil {
+			fmt.Printf("%sError creating snapshot: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		fmt.Printf("%sCreated snapshot [%s%s%s]%s\n", config.Green, config.Purple, snapshot.ID, config.Green, config.Reset)
+	},
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore [snapshot]",
+	Short: "Restore the store from a snapshot",
+	Long: `Restore the store from a snapshot.
+
+The snapshot can be given as its full ID or any unique prefix, see
+'todo backup list'. The changes are shown and confirmed before anything
+is written, and the current state is saved as a manual snapshot first.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		s, err := fs.Open()
+		if err != nil {
+			fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+		defer s.Close()
+
+		snapshot, err := s.FindSnapshot(args[0])
+		if err != nil {
+			fmt.Printf("%s%v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		restored, err := s.LoadSnapshot(snapshot)
+		if err != nil {
+			fmt.Printf("%sError reading snapshot: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		c, err := s.Load()
+		if err != nil {
+			fmt.Printf("%sError loading config: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		changes := describeChanges(c, restored)
+		if len(changes) == 0 {
+			fmt.Printf("%sStore already matches snapshot [%s]%s\n", config.Yellow, snapshot.ID, config.Reset)
+			return
+		}
+
+		fmt.Printf("%sRestoring snapshot [%s%s%s] would make these changes:%s\n",
+			config.Blue+config.Bold, config.Purple, snapshot.ID, config.Blue+config.Bold, config.Reset)
+		for _, change := range changes {
+			fmt.Printf("  %s\n", change)
+		}
+
+		if !yes && !confirm("Restore this snapshot?") {
+			fmt.Printf("%sRestore cancelled%s\n", config.Yellow, config.Reset)
+			return
+		}
+
+		if _, err := s.CreateSnapshot(); err != nil {
+			fmt.Printf("%sError taking snapshot: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		if err = s.Save(restored); err != nil {
+			fmt.Printf("%sError saving config: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		fmt.Printf("%sRestored snapshot [%s%s%s]%s\n", config.Green, config.Purple, snapshot.ID, config.Green, config.Reset)
+	},
+}
+
+func init() {
+	backupCmd.AddCommand(backupListCmd)
+	backupCmd.AddCommand(backupCreateCmd)
+
+	restoreCmd.Flags().BoolP("yes", "y", false, "Restore without asking for confirmation")
+}
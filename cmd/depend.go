@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dorukozerr/todo-cli/internal/config"
+	"github.com/dorukozerr/todo-cli/internal/fs"
+	"github.com/dorukozerr/todo-cli/internal/types"
+	"github.com/spf13/cobra"
+)
+
+var dependCmd = &cobra.Command{
+	Use:   "depend [todo-id]",
+	Short: "Mark a todo as blocked by other todos",
+	Long: `Mark a todo as blocked until other todos are completed:
+- depend <id> --on <other-id>: <id> waits for <other-id>
+- depend <id> --remove <other-id>: drop that dependency
+- depend <id>: show what <id> depends on
+
+--on and --remove can be repeated. Dependencies that would form a cycle are
+rejected.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ref := args[0]
+		on, _ := cmd.Flags().GetStringSlice("on")
+		remove, _ := cmd.Flags().GetStringSlice("remove")
+
+		s, err := fs.Open()
+		if err != nil {
+			fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+		defer s.Close()
+
+		c, err := s.Load()
+		if err != nil {
+			fmt.Printf("%sError loading config: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		todo := resolveTodo(c, ref)
+		if todo == nil {
+			return
+		}
+		id, number := todo.ID, todo.Number
+
+		if len(on) == 0 && len(remove) == 0 {
+			if len(todo.DependsOn) == 0 {
+				fmt.Printf("%sTodo [%d] has no dependencies%s\n", config.Yellow, number, config.Reset)
+				return
+			}
+			fmt.Printf("%sTodo [%s%d%s] depends on:%s\n", config.Blue+config.Bold, config.Purple, number, config.Blue+config.Bold, config.Reset)
+			for _, depID := range todo.DependsOn {
+				status := "missing"
+				if dep := findTodo(c, depID); dep != nil {
+					status = dep.Status + ": " + dep.Task
+				}
+				fmt.Printf("  [%s%s%s] %s\n", config.Purple, todoRef(c, depID), config.Reset, status)
+			}
+			return
+		}
+
+		for _, depRef := range on {
+			dep := resolveTodo(c, depRef)
+			if dep == nil {
+				return
+			}
+			if dep.ID == id {
+				fmt.Printf("%sA todo cannot depend on itself%s\n", config.Red, config.Reset)
+				return
+			}
+			if path := dependencyPath(c, dep.ID, id); path != nil {
+				for i, pathID := range path {
+					path[i] = todoRef(c, pathID)
+				}
+				fmt.Printf("%sCannot depend on [%d]: it already waits for [%d] (%s)%s\n",
+					config.Red, dep.Number, number, strings.Join(path, " -> "), config.Reset)
+				return
+			}
+			if !slices.Contains(todo.DependsOn, dep.ID) {
+				todo.DependsOn = append(todo.DependsOn, dep.ID)
+			}
+		}
+
+		var removed []string
+		for _, depRef := range remove {
+			dep := resolveTodo(c, depRef)
+			if dep == nil {
+				return
+			}
+			removed = append(removed, dep.ID)
+		}
+		todo.DependsOn = slices.DeleteFunc(todo.DependsOn, func(depID string) bool {
+			return slices.Contains(removed, depID)
+		})
+		if len(todo.DependsOn) == 0 {
+			todo.DependsOn = nil
+		}
+		todo.UpdatedAt = time.Now()
+
+		if err = s.PutTodo(*todo); err != nil {
+			fmt.Printf("%sError saving config: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		if len(todo.DependsOn) == 0 {
+			fmt.Printf("%sTodo [%s%d%s] no longer depends on anything%s\n", config.Green, config.Purple, number, config.Green, config.Reset)
+			return
+		}
+		fmt.Printf("%sTodo [%s%d%s] now depends on %s%s\n", config.Green,
+			config.Purple, number, config.Green,
+			formatTodoRefs(c, todo.DependsOn), config.Reset)
+	},
+}
+
+// dependencyPath returns the chain of dependencies leading from one todo to
+// another, or nil when from does not wait for to.
+func dependencyPath(c *types.Config, from, to string) []string {
+	seen := make(map[string]bool)
+	var walk func(id string) []string
+	walk = func(id string) []string {
+		if id == to {
+			return []string{id}
+		}
+		if seen[id] {
+			return nil
+		}
+		seen[id] = true
+
+		todo := findTodo(c, id)
+		if todo == nil {
+			return nil
+		}
+		for _, depID := range todo.DependsOn {
+			if path := walk(depID); path != nil {
+				return append([]string{id}, path...)
+			}
+		}
+		return nil
+	}
+	return walk(from)
+}
+
+// blockers returns the open todos that todo is waiting for. Dependencies
+// that were deleted or archived no longer block.
+func blockers(c *types.Config, todo types.Todo) []string {
+	var open []string
+	for _, depID := range todo.DependsOn {
+		if dep := findTodo(c, depID); dep != nil && !dep.Completed {
+			open = append(open, depID)
+		}
+	}
+	return open
+}
+
+// blockedTodos returns the IDs of the open todos that are waiting for
+// something.
+func blockedTodos(c *types.Config) map[string]bool {
+	blocked := make(map[string]bool)
+	for _, todo := range c.Todos {
+		if !todo.Completed && len(blockers(c, todo)) > 0 {
+			blocked[todo.ID] = true
+		}
+	}
+	return blocked
+}
+
+func formatTodoRefs(c *types.Config, ids []string) string {
+	refs := make([]string, len(ids))
+	for i, id := range ids {
+		refs[i] = "#" + todoRef(c, id)
+	}
+	return strings.Join(refs, ", ")
+}
+
+// todoRef is how a referenced todo is shown: its number, or the start of
+// its UUID when it is no longer in the store.
+func todoRef(c *types.Config, id string) string {
+	if todo := findTodo(c, id); todo != nil {
+		return strconv.Itoa(todo.Number)
+	}
+	return id[:min(len(id), 8)]
+}
+
+func init() {
+	dependCmd.Flags().StringSlice("on", nil, "ID of a todo this one waits for")
+	dependCmd.Flags().StringSlice("remove", nil, "ID of a dependency to drop")
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/dorukozerr/todo-cli/internal/config"
+	"github.com/dorukozerr/todo-cli/internal/types"
+	"github.com/dorukozerr/todo-cli/internal/utils"
+)
+
+// describeChanges lists, one line each, what turning from into to changes.
+func describeChanges(from, to *types.Config) []string {
+	var changes []string
+
+	if from.ActiveGroup != to.ActiveGroup {
+		changes = append(changes, fmt.Sprintf("%s~%s active group: %s -> %s", config.Yellow, config.Reset,
+			displayGroupName(from.ActiveGroup), displayGroupName(to.ActiveGroup)))
+	}
+
+	for _, group := range to.Groups {
+		if !groupExists(from.Groups, group.Name) {
+			changes = append(changes, fmt.Sprintf("%s+%s group %s", config.Green, config.Reset, group.Name))
+		}
+	}
+	for _, group := range from.Groups {
+		if !groupExists(to.Groups, group.Name) {
+			changes = append(changes, fmt.Sprintf("%s-%s group %s", config.Red, config.Reset, group.Name))
+		}
+	}
+
+	for _, def := range to.Fields {
+		if _, ok := utils.FindField(from.Fields, def.Name); !ok {
+			changes = append(changes, fmt.Sprintf("%s+%s field %s (%s)", config.Green, config.Reset, def.Name, def.Type))
+		}
+	}
+	for _, def := range from.Fields {
+		if _, ok := utils.FindField(to.Fields, def.Name); !ok {
+			changes = append(changes, fmt.Sprintf("%s-%s field %s", config.Red, config.Reset, def.Name))
+		}
+	}
+
+	fromTodos := make(map[string]types.Todo)
+	for _, todo := range from.Todos {
+		fromTodos[todo.ID] = todo
+	}
+	toTodos := make(map[string]types.Todo)
+	for _, todo := range to.Todos {
+		toTodos[todo.ID] = todo
+	}
+
+	for _, todo := range to.Todos {
+		before, ok := fromTodos[todo.ID]
+		if !ok {
+			changes = append(changes, fmt.Sprintf("%s+%s [%s%d%s] %s", config.Green, config.Reset,
+				config.Purple, todo.Number, config.Reset, todo.Task))
+			continue
+		}
+		if fields := changedFields(before, todo); len(fields) > 0 {
+			changes = append(changes, fmt.Sprintf("%s~%s [%s%d%s] %s: %s", config.Yellow, config.Reset,
+				config.Purple, todo.Number, config.Reset, todo.Task, strings.Join(fields, ", ")))
+		}
+	}
+	for _, todo := range from.Todos {
+		if _, ok := toTodos[todo.ID]; !ok {
+			changes = append(changes, fmt.Sprintf("%s-%s [%s%d%s] %s", config.Red, config.Reset,
+				config.Purple, todo.Number, config.Reset, todo.Task))
+		}
+	}
+
+	return changes
+}
+
+// changedFields compares two todos through their JSON form so that new
+// fields show up without touching this function.
+func changedFields(before, after types.Todo) []string {
+	var beforeFields, afterFields map[string]any
+
+	beforeData, _ := json.Marshal(before)
+	afterData, _ := json.Marshal(after)
+	json.Unmarshal(beforeData, &beforeFields)
+	json.Unmarshal(afterData, &afterFields)
+
+	keys := make(map[string]bool)
+	for key := range beforeFields {
+		keys[key] = true
+	}
+	for key := range afterFields {
+		keys[key] = true
+	}
+
+	var names []string
+	for key := range keys {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+
+	var fields []string
+	for _, key := range names {
+		if reflect.DeepEqual(beforeFields[key], afterFields[key]) {
+			continue
+		}
+		fields = append(fields, fmt.Sprintf("%s %s -> %s", key, formatField(beforeFields[key]), formatField(afterFields[key])))
+	}
+
+	return fields
+}
+
+func formatField(value any) string {
+	if value == nil {
+		return "none"
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+func displayGroupName(name string) string {
+	if name == "" {
+		return "default"
+	}
+	return name
+}
+
+func confirm(prompt string) bool {
+	fmt.Printf("%s [y/N] ", prompt)
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		fmt.Println()
+		return false
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dorukozerr/todo-cli/internal/config"
+	"github.com/dorukozerr/todo-cli/internal/fs"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the store for integrity problems",
+	Long: `Check the store for integrity problems and, with --fix, repair them.
+
+Every problem is printed on its own line, starting with one of these codes:
+  empty-id               a todo has no UUID
+  duplicate-id           two todos share a UUID
+  missing-number         a todo has no number
+  duplicate-number       two todos share a number
+  invalid-urgency        a todo's urgency is outside 1-5
+  missing-group          a todo belongs to a group that does not exist
+  duplicate-group        a group is defined more than once
+  missing-group-id       a group has no UUID
+  unknown-active-group   the active group does not exist
+  missing-parent         a subtask's parent todo does not exist
+  missing-dependency     a todo depends on a todo that is neither in the store
+                         nor archived
+  invalid-workflow       the store's workflow cannot be used
+  unknown-status         a todo's status is not a state of the workflow
+  status-mismatch        a todo's completed flag disagrees with its status
+  corrupt-history        the undo history has lines that cannot be read
+
+--fix takes a manual snapshot first, so 'todo restore' can undo the repair.
+The command exits with status 1 while problems remain.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fix, _ := cmd.Flags().GetBool("fix")
+
+		s, err := fs.Open()
+		if err != nil {
+			return fmt.Errorf("%serror opening store: %v%s", config.Red, err, config.Reset)
+		}
+		defer s.Close()
+
+		problems, snapshot, err := s.Doctor(fix)
+		if err != nil {
+			return fmt.Errorf("%s%v%s", config.Red, err, config.Reset)
+		}
+
+		if len(problems) == 0 {
+			fmt.Printf("%sNo problems found in %s%s\n", config.Green, s.Path(), config.Reset)
+			return nil
+		}
+
+		for _, problem := range problems {
+			fmt.Printf("%s%s%s %s\n", config.Red, problem.Code, config.Reset, problem.Message)
+			if fix {
+				fmt.Printf("  %sfixed:%s %s\n", config.Green, config.Reset, problem.Fix)
+			} else {
+				fmt.Printf("  %sfix:%s %s\n", config.Cyan, config.Reset, problem.Fix)
+			}
+		}
+
+		if !fix {
+			return fmt.Errorf("%d problem(s) found, run 'todo doctor --fix' to repair them", len(problems))
+		}
+
+		fmt.Printf("\n%sRepaired %d problem(s), the previous state is in snapshot %s%s\n",
+			config.Green, len(problems), snapshot.ID, config.Reset)
+		return nil
+	},
+}
+
+func init() {
+	doctorCmd.Flags().Bool("fix", false, "Repair the problems found, after taking a backup")
+}
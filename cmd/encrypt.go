@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/dorukozerr/todo-cli/internal/config"
+	"github.com/dorukozerr/todo-cli/internal/fs"
+	"github.com/spf13/cobra"
+)
+
+var encryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Encrypt the todo store with a passphrase",
+	Long: `Encrypt the todo store, its undo history, its snapshots and the
+backups left by migrations with AES-256-GCM under a key derived from a
+passphrase.
+
+The passphrase is read from $TODO_CLI_PASSPHRASE, or prompted for twice.
+Every later command needs the same passphrase, from the environment or a
+prompt.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		s, err := fs.Open()
+		if err != nil {
+			fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+		defer s.Close()
+
+		encrypted, err := s.Encrypted()
+		if err != nil {
+			fmt.Printf("%sError loading config: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+		if encrypted {
+			fmt.Printf("%sStore %s is already encrypted%s\n", config.Yellow, s.Path(), config.Reset)
+			return
+		}
+
+		passphrase := os.Getenv(fs.EnvPassphrase)
+		if passphrase == "" {
+			passphrase, err = readPassphrase("New passphrase: ")
+			if err != nil {
+				fmt.Printf("%s%v%s\n", config.Red, err, config.Reset)
+				return
+			}
+
+			again, err := readPassphrase("Repeat passphrase: ")
+			if err != nil {
+				fmt.Printf("%s%v%s\n", config.Red, err, config.Reset)
+				return
+			}
+			if again != passphrase {
+				fmt.Printf("%sPassphrases do not match%s\n", config.Red, config.Reset)
+				return
+			}
+		}
+
+		if err = s.Encrypt(passphrase); err != nil {
+			fmt.Printf("%sError encrypting store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		fmt.Printf("%sEncrypted store %s%s%s\n", config.Green, config.Bold, s.Path(), config.Reset)
+	},
+}
+
+var decryptCmd = &cobra.Command{
+	Use:   "decrypt",
+	Short: "Turn an encrypted todo store back into plaintext",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		s, err := fs.Open()
+		if err != nil {
+			fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+		defer s.Close()
+
+		encrypted, err := s.Encrypted()
+		if err != nil {
+			fmt.Printf("%sError loading config: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+		if !encrypted {
+			fmt.Printf("%sStore %s is not encrypted%s\n", config.Yellow, s.Path(), config.Reset)
+			return
+		}
+
+		if err = s.Decrypt(); err != nil {
+			fmt.Printf("%sError decrypting store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		fmt.Printf("%sDecrypted store %s%s%s\n", config.Green, config.Bold, s.Path(), config.Reset)
+	},
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dorukozerr/todo-cli/internal/config"
+	"github.com/dorukozerr/todo-cli/internal/fs"
+	"github.com/dorukozerr/todo-cli/internal/types"
+	"github.com/dorukozerr/todo-cli/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain [todo-id]",
+	Short: "Show how a todo's score is built up",
+	Long: `Show the parts of the score 'todo list --sort score' orders by: the
+urgency level, the todo's age, how close its due date is, whether it blocks
+or waits for other open todos and its tags. Each part is a factor times a
+coefficient from the score_* settings, see 'todo config list'.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ref := args[0]
+
+		s, err := fs.Open()
+		if err != nil {
+			fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+		defer s.Close()
+
+		c, err := s.Load()
+		if err != nil {
+			fmt.Printf("%sError loading config: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		todo := resolveTodo(c, ref)
+		if todo == nil {
+			return
+		}
+
+		if todo.Completed {
+			fmt.Printf("%sTodo [%d] is %s, only open todos are scored%s\n", config.Yellow, todo.Number, todo.Status, config.Reset)
+			return
+		}
+
+		terms := scoreTerms(c, *todo, blockingTodos(c), time.Now())
+
+		fmt.Printf("\n%s[%s%d%s] %s%s\n", config.Bold, config.Purple, todo.Number, config.Reset+config.Bold, todo.Task, config.Reset)
+		fmt.Println(strings.Repeat("=", 60))
+		for _, term := range terms {
+			fmt.Printf("%s%-9s%s %-26s %5.2f x %5.1f = %s%+6.1f%s\n",
+				config.Cyan, term.Name, config.Reset,
+				term.Reason, term.Factor, term.Coefficient,
+				scoreColor(term.Value()), term.Value(), config.Reset)
+		}
+		fmt.Println(strings.Repeat("-", 60))
+		fmt.Printf("%s%-53s %6.1f%s\n", config.Bold, "score", utils.Score(terms), config.Reset)
+	},
+}
+
+// scoreTerms scores todo against the rest of the store. blocking is the
+// result of blockingTodos, passed in so listings compute it once.
+func scoreTerms(c *types.Config, todo types.Todo, blocking map[string]bool, now time.Time) []utils.ScoreTerm {
+	blocked := len(blockers(c, todo)) > 0
+	return utils.ScoreTerms(todo, userSettings, blocked, blocking[todo.ID], now)
+}
+
+// todoScores returns the score of every open todo in todos. Completed todos
+// are not scored and count as 0.
+func todoScores(c *types.Config, todos []types.Todo, now time.Time) map[string]float64 {
+	blocking := blockingTodos(c)
+	scores := make(map[string]float64, len(todos))
+	for _, todo := range todos {
+		if !todo.Completed {
+			scores[todo.ID] = utils.Score(scoreTerms(c, todo, blocking, now))
+		}
+	}
+	return scores
+}
+
+// blockingTodos returns the IDs of the open todos that an open todo is
+// waiting for.
+func blockingTodos(c *types.Config) map[string]bool {
+	blocking := make(map[string]bool)
+	for _, todo := range c.Todos {
+		if todo.Completed {
+			continue
+		}
+		for _, depID := range blockers(c, todo) {
+			blocking[depID] = true
+		}
+	}
+	return blocking
+}
+
+func scoreColor(value float64) string {
+	if value < 0 {
+		return config.Red
+	}
+	return config.Green
+}
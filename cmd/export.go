@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dorukozerr/todo-cli/internal/config"
+	"github.com/dorukozerr/todo-cli/internal/fs"
+	"github.com/dorukozerr/todo-cli/internal/types"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Print todos as CSV or JSON",
+	Long: `Print the todos of the active group, or of every group with --all-groups,
+as CSV or JSON on stdout. Completed todos are included. CSV has one column
+per custom field, after the built-in columns.
+
+CSV holds the same todos as JSON. Tags and dependency UUIDs are separated by
+spaces and links by newlines; instead of the individual time log entries,
+time_tracked is the total in seconds.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		allGroups, _ := cmd.Flags().GetBool("all-groups")
+
+		if format != "csv" && format != "json" {
+			fmt.Printf("%s--format must be csv or json%s\n", config.Red, config.Reset)
+			return
+		}
+
+		s, err := fs.Open()
+		if err != nil {
+			fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+		defer s.Close()
+
+		c, err := s.Load()
+		if err != nil {
+			fmt.Printf("%sError loading config: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		todos := []types.Todo{}
+		for _, todo := range c.Todos {
+			if allGroups || todo.Group == c.ActiveGroup {
+				todos = append(todos, todo)
+			}
+		}
+
+		if format == "json" {
+			err = exportJSON(todos)
+		} else {
+			err = exportCSV(c.Fields, todos)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%sError exporting todos: %v%s\n", config.Red, err, config.Reset)
+		}
+	},
+}
+
+func exportJSON(todos []types.Todo) error {
+	data, err := json.MarshalIndent(todos, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Println(string(data))
+	return err
+}
+
+func exportCSV(defs []types.FieldDef, todos []types.Todo) error {
+	fields := fieldNames(defs, todos)
+	header := []string{"number", "id", "group", "task", "status", "urgency", "tags", "parent", "due", "created_at", "updated_at", "completed_at",
+		"depends_on", "wait", "recur", "links", "notes", "time_tracked"}
+
+	w := csv.NewWriter(os.Stdout)
+	if err := w.Write(append(header, fields...)); err != nil {
+		return err
+	}
+
+	stamp := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.Format(time.RFC3339)
+	}
+	now := time.Now()
+	for _, todo := range todos {
+		record := []string{
+			strconv.Itoa(todo.Number),
+			todo.ID,
+			displayGroupName(todo.Group),
+			todo.Task,
+			todo.Status,
+			strconv.Itoa(todo.Urgency),
+			strings.Join(todo.Tags, " "),
+			todo.Parent,
+			stamp(todo.Due),
+			stamp(&todo.CreatedAt),
+			stamp(&todo.UpdatedAt),
+			stamp(todo.CompletedAt),
+			strings.Join(todo.DependsOn, " "),
+			stamp(todo.Wait),
+			todo.Recur,
+			strings.Join(todo.Links, "\n"),
+			todo.Notes,
+			strconv.FormatInt(int64(totalTime(todo, now)/time.Second), 10),
+		}
+		for _, name := range fields {
+			record = append(record, todo.Fields[name])
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+func init() {
+	exportCmd.Flags().String("format", "csv", "Output format, csv or json")
+	exportCmd.Flags().Bool("all-groups", false, "Export todos from all groups")
+}
@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dorukozerr/todo-cli/internal/config"
+	"github.com/dorukozerr/todo-cli/internal/fs"
+	"github.com/dorukozerr/todo-cli/internal/types"
+	"github.com/dorukozerr/todo-cli/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var wherePattern = regexp.MustCompile(`^([a-z][a-z0-9_]*)\s*(!=|<=|>=|=|<|>)\s*(.*)$`)
+
+var configFieldCmd = &cobra.Command{
+	Use:   "field",
+	Short: "Declare custom fields for todos",
+	Long: `Declare custom fields todos can carry, set with 'todo add/update --set':
+- config field list: Show the declared fields
+- config field add <name> string|number|date: Declare a field
+- config field add <name> enum <value>...: Declare a field with fixed values
+- config field remove <name>: Forget a field; values already set are kept
+
+Fields can be filtered on with 'todo list --where', sorted on with
+'todo list --sort <name>' and are included in 'todo export'.`,
+}
+
+var configFieldListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show the declared custom fields",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		s, err := fs.Open()
+		if err != nil {
+			fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+		defer s.Close()
+
+		c, err := s.Load()
+		if err != nil {
+			fmt.Printf("%sError loading config: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		if len(c.Fields) == 0 {
+			fmt.Printf("%sNo custom fields declared, add one with 'todo config field add'%s\n", config.Yellow, config.Reset)
+			return
+		}
+
+		fmt.Printf("%sCustom fields:%s\n", config.Blue+config.Bold, config.Reset)
+		for _, def := range c.Fields {
+			kind := def.Type
+			if def.Type == utils.FieldEnum {
+				kind += " " + strings.Join(def.Values, "|")
+			}
+			fmt.Printf("  %s%-20s%s %s\n", config.Cyan, def.Name, config.Reset, kind)
+		}
+	},
+}
+
+var configFieldAddCmd = &cobra.Command{
+	Use:   "add [name] [type] [value...]",
+	Short: "Declare a custom field",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		def := types.FieldDef{Name: args[0], Type: args[1], Values: args[2:]}
+		if len(def.Values) == 0 {
+			def.Values = nil
+		}
+
+		err := updateFieldDefs(func(defs []types.FieldDef) ([]types.FieldDef, error) {
+			if _, ok := utils.FindField(defs, def.Name); ok {
+				return nil, fmt.Errorf("field '%s' already exists", def.Name)
+			}
+			return append(defs, def), nil
+		})
+		if err != nil {
+			fmt.Printf("%s%v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		fmt.Printf("%sDeclared field %s%s%s (%s)%s\n", config.Green, config.Cyan, def.Name, config.Green, def.Type, config.Reset)
+	},
+}
+
+var configFieldRemoveCmd = &cobra.Command{
+	Use:   "remove [name]",
+	Short: "Forget a custom field",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+
+		err := updateFieldDefs(func(defs []types.FieldDef) ([]types.FieldDef, error) {
+			if _, ok := utils.FindField(defs, name); !ok {
+				return nil, fmt.Errorf("field '%s' is not declared", name)
+			}
+			return slices.DeleteFunc(defs, func(def types.FieldDef) bool { return def.Name == name }), nil
+		})
+		if err != nil {
+			fmt.Printf("%s%v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		fmt.Printf("%sRemoved field %s%s%s, values already set on todos are kept%s\n", config.Green, config.Cyan, name, config.Green, config.Reset)
+	},
+}
+
+// updateFieldDefs changes the fields declared in the store, which is
+// recorded for undo like any other write.
+func updateFieldDefs(update func(defs []types.FieldDef) ([]types.FieldDef, error)) error {
+	s, err := fs.Open()
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	c, err := s.Load()
+	if err != nil {
+		return err
+	}
+
+	defs, err := update(slices.Clone(c.Fields))
+	if err != nil {
+		return err
+	}
+	if err := utils.ValidateFieldDefs(defs); err != nil {
+		return err
+	}
+
+	c.Fields = defs
+	if len(c.Fields) == 0 {
+		c.Fields = nil
+	}
+	return s.Save(c)
+}
+
+// parseSetArgs reads --set key=value flags against the declared fields. An
+// empty value unsets the field.
+func parseSetArgs(defs []types.FieldDef, args []string, now time.Time) (map[string]string, []string, error) {
+	set := make(map[string]string)
+	var unset []string
+	for _, arg := range args {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid --set '%s', use key=value", arg)
+		}
+
+		name = strings.TrimSpace(name)
+		def, ok := utils.FindField(defs, name)
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown field '%s', declare it with 'todo config field add'", name)
+		}
+
+		if strings.TrimSpace(value) == "" {
+			unset = append(unset, name)
+			continue
+		}
+		value, err := utils.ParseFieldValue(def, value, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		set[name] = value
+	}
+	return set, unset, nil
+}
+
+// applyFields returns a copy of fields with set and unset applied, or nil
+// when no field is left.
+func applyFields(fields, set map[string]string, unset []string) map[string]string {
+	updated := maps.Clone(fields)
+	if updated == nil {
+		updated = make(map[string]string)
+	}
+	maps.Copy(updated, set)
+	for _, name := range unset {
+		delete(updated, name)
+	}
+	if len(updated) == 0 {
+		return nil
+	}
+	return updated
+}
+
+// fieldNames returns the names of the fields set on todos, declared fields
+// first in their declared order, then any others alphabetically.
+func fieldNames(defs []types.FieldDef, todos []types.Todo) []string {
+	present := make(map[string]bool)
+	for _, todo := range todos {
+		for name := range todo.Fields {
+			present[name] = true
+		}
+	}
+
+	var names []string
+	for _, def := range defs {
+		if present[def.Name] {
+			names = append(names, def.Name)
+			delete(present, def.Name)
+		}
+	}
+	var others []string
+	for name := range present {
+		others = append(others, name)
+	}
+	sort.Strings(others)
+	return append(names, others...)
+}
+
+func formatFields(defs []types.FieldDef, fields map[string]string) []string {
+	var pairs []string
+	for _, name := range fieldNames(defs, []types.Todo{{Fields: fields}}) {
+		value := fields[name]
+		if strings.ContainsAny(value, " ,\"") {
+			value = strconv.Quote(value)
+		}
+		pairs = append(pairs, name+"="+value)
+	}
+	return pairs
+}
+
+// fieldFilter is one --where condition, such as story_points>=3.
+type fieldFilter struct {
+	def   types.FieldDef
+	op    string
+	value string
+}
+
+func parseWhere(defs []types.FieldDef, expr string, now time.Time) (fieldFilter, error) {
+	m := wherePattern.FindStringSubmatch(strings.TrimSpace(expr))
+	if m == nil {
+		return fieldFilter{}, fmt.Errorf("invalid --where '%s', use e.g. env=prod or story_points>=3", expr)
+	}
+
+	def, ok := utils.FindField(defs, m[1])
+	if !ok {
+		return fieldFilter{}, fmt.Errorf("unknown field '%s', declare it with 'todo config field add'", m[1])
+	}
+
+	filter := fieldFilter{def: def, op: m[2]}
+	if m[3] == "" {
+		if filter.op != "=" && filter.op != "!=" {
+			return fieldFilter{}, fmt.Errorf("invalid --where '%s', %s needs a value", expr, filter.op)
+		}
+		return filter, nil
+	}
+
+	value, err := utils.ParseFieldValue(def, m[3], now)
+	if err != nil {
+		return fieldFilter{}, err
+	}
+	filter.value = value
+	return filter, nil
+}
+
+// match reports whether todo satisfies the condition. An empty value
+// matches todos without the field for '=' and with it for '!='; ordering
+// comparisons never match todos without the field.
+func (f fieldFilter) match(todo types.Todo) bool {
+	value, ok := todo.Fields[f.def.Name]
+	if f.value == "" {
+		return ok == (f.op == "!=")
+	}
+	if !ok {
+		return f.op == "!="
+	}
+
+	cmp := utils.CompareFieldValues(f.def, value, f.value)
+	switch f.op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	}
+	return cmp >= 0
+}
+
+func init() {
+	configFieldCmd.AddCommand(configFieldListCmd)
+	configFieldCmd.AddCommand(configFieldAddCmd)
+	configFieldCmd.AddCommand(configFieldRemoveCmd)
+}
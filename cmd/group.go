@@ -7,6 +7,7 @@ import (
 	"github.com/dorukozerr/todo-cli/internal/config"
 	"github.com/dorukozerr/todo-cli/internal/fs"
 	"github.com/dorukozerr/todo-cli/internal/types"
+	"github.com/dorukozerr/todo-cli/internal/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -50,7 +51,13 @@ Without flags, shows the current active group.`,
 			return fmt.Errorf("%sonly one flag can be used at a time%s", config.Red, config.Reset)
 		}
 
-		c, err := fs.GetConfig()
+		s, err := fs.Open()
+		if err != nil {
+			return fmt.Errorf("%serror opening store: %v%s", config.Red, err, config.Reset)
+		}
+		defer s.Close()
+
+		c, err := s.Load()
 		if err != nil {
 			return fmt.Errorf("%serror loading config: %v%s", config.Red, err, config.Reset)
 		}
@@ -61,11 +68,11 @@ Without flags, shows the current active group.`,
 		case activeFlag:
 			return handleActiveGroup(c)
 		case switchGroup != "":
-			return handleSwitchGroup(c, switchGroup)
+			return handleSwitchGroup(s, c, switchGroup)
 		case createGroup != "":
-			return handleCreateGroup(c, createGroup)
+			return handleCreateGroup(s, c, createGroup)
 		case deleteGroup != "":
-			return handleDeleteGroup(c, deleteGroup)
+			return handleDeleteGroup(s, c, deleteGroup)
 		default:
 			return handleDefaultGroupDisplay(c)
 		}
@@ -96,8 +103,8 @@ func handleActiveGroup(c *types.Config) error {
 		activeGroup = "default"
 	}
 
-	todoCount := countTodosInGroup(c.Todos, activeGroup)
-	incompleteCount := countIncompleteTodosInGroup(c.Todos, activeGroup)
+	todoCount := countTodosInGroup(c.Todos, c.ActiveGroup)
+	incompleteCount := countIncompleteTodosInGroup(c.Todos, c.ActiveGroup)
 
 	fmt.Printf("%sActive group:%s %s%s%s\n", config.Cyan, config.Reset, config.Green+config.Bold, activeGroup, config.Reset)
 	fmt.Printf("%sTotal todos:%s %s%d%s (%s%d incomplete%s)\n",
@@ -107,7 +114,7 @@ func handleActiveGroup(c *types.Config) error {
 	return nil
 }
 
-func handleSwitchGroup(c *types.Config, groupName string) error {
+func handleSwitchGroup(s fs.Store, c *types.Config, groupName string) error {
 	groupName = strings.TrimSpace(groupName)
 	if groupName == "" {
 		return fmt.Errorf("%sgroup name cannot be empty%s", config.Red, config.Reset)
@@ -117,13 +124,13 @@ func handleSwitchGroup(c *types.Config, groupName string) error {
 		return fmt.Errorf("%sgroup '%s' does not exist. Use 'todo group --create %s' to create it first%s", config.Red, groupName, groupName, config.Reset)
 	}
 
-	c.ActiveGroup = groupName
-	if err := fs.SaveConfig(c); err != nil {
+	c.ActiveGroup = groupKey(groupName)
+	if err := s.Save(c); err != nil {
 		return fmt.Errorf("%serror saving config: %v%s", config.Red, err, config.Reset)
 	}
 
-	todoCount := countTodosInGroup(c.Todos, groupName)
-	incompleteCount := countIncompleteTodosInGroup(c.Todos, groupName)
+	todoCount := countTodosInGroup(c.Todos, c.ActiveGroup)
+	incompleteCount := countIncompleteTodosInGroup(c.Todos, c.ActiveGroup)
 
 	fmt.Printf("%sSwitched to group '%s%s%s'%s\n", config.Green, config.Bold, groupName, config.Green, config.Reset)
 	fmt.Printf("This group has %s%d%s todos (%s%d%s incomplete)\n",
@@ -132,7 +139,7 @@ func handleSwitchGroup(c *types.Config, groupName string) error {
 	return nil
 }
 
-func handleCreateGroup(c *types.Config, groupName string) error {
+func handleCreateGroup(s fs.Store, c *types.Config, groupName string) error {
 	groupName = strings.TrimSpace(groupName)
 	if groupName == "" {
 		return fmt.Errorf("%sgroup name cannot be empty%s", config.Red, config.Reset)
@@ -146,10 +153,10 @@ func handleCreateGroup(c *types.Config, groupName string) error {
 		return fmt.Errorf("%sgroup '%s' already exists%s", config.Red, groupName, config.Reset)
 	}
 
-	newGroup := types.Group{Name: groupName}
+	newGroup := types.Group{ID: utils.GenerateUUID(), Name: groupName}
 	c.Groups = append(c.Groups, newGroup)
 
-	if err := fs.SaveConfig(c); err != nil {
+	if err := s.Save(c); err != nil {
 		return fmt.Errorf("%serror saving config: %v%s", config.Red, err, config.Reset)
 	}
 
@@ -158,7 +165,7 @@ func handleCreateGroup(c *types.Config, groupName string) error {
 	return nil
 }
 
-func handleDeleteGroup(c *types.Config, groupName string) error {
+func handleDeleteGroup(s fs.Store, c *types.Config, groupName string) error {
 	groupName = strings.TrimSpace(groupName)
 	if groupName == "" {
 		return fmt.Errorf("%sgroup name cannot be empty%s", config.Red, config.Reset)
@@ -176,7 +183,7 @@ func handleDeleteGroup(c *types.Config, groupName string) error {
 
 	for i := range c.Todos {
 		if c.Todos[i].Group == groupName {
-			c.Todos[i].Group = "default"
+			c.Todos[i].Group = ""
 		}
 	}
 
@@ -189,11 +196,11 @@ func handleDeleteGroup(c *types.Config, groupName string) error {
 	c.Groups = newGroups
 
 	if c.ActiveGroup == groupName {
-		c.ActiveGroup = "default"
+		c.ActiveGroup = ""
 		fmt.Printf("%sSwitched active group to 'default'%s\n", config.Yellow, config.Reset)
 	}
 
-	if err := fs.SaveConfig(c); err != nil {
+	if err := s.Save(c); err != nil {
 		return fmt.Errorf("%serror saving config: %v%s", config.Red, err, config.Reset)
 	}
 
@@ -226,6 +233,15 @@ func handleDefaultGroupDisplay(c *types.Config) error {
 	return nil
 }
 
+// groupKey maps the user-facing "default" group name to the empty name it
+// is stored under.
+func groupKey(name string) string {
+	if name == "default" {
+		return ""
+	}
+	return name
+}
+
 func groupExists(groups []types.Group, name string) bool {
 	for _, group := range groups {
 		if group.Name == name {
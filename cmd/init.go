@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/dorukozerr/todo-cli/internal/config"
+	"github.com/dorukozerr/todo-cli/internal/fs"
+	"github.com/spf13/cobra"
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create a project todo store in the current directory",
+	Long: `Create a .todo.json in the current directory.
+
+Whenever todo runs in this directory or any directory below it, the
+project store is used instead of the global one, the way git finds .git.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		workDir, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("%sError reading working directory: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		path, err := fs.InitProjectStore(workDir)
+		if err != nil {
+			fmt.Printf("%sError creating project store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		fmt.Printf("%sCreated project store %s%s%s\n", config.Green, config.Bold, path, config.Reset)
+		fmt.Printf("Files named %s%s.*%s next to it are local state and can be ignored by git\n",
+			config.Cyan, fs.ProjectFileName, config.Reset)
+	},
+}
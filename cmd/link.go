@@ -0,0 +1,390 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dorukozerr/todo-cli/internal/config"
+	"github.com/dorukozerr/todo-cli/internal/fs"
+	"github.com/spf13/cobra"
+)
+
+const (
+	linkURL  = "url"
+	linkFile = "file"
+	linkCode = "code"
+)
+
+var (
+	schemePattern   = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*://`)
+	codeLinkPattern = regexp.MustCompile(`^(.+):(\d+)(?::\d+)?$`)
+
+	// openSchemes are the URL schemes 'todo open' hands to an opener.
+	openSchemes = []string{"http", "https", "mailto"}
+
+	// windowsExecutables are opened by running them, whatever PATHEXT says.
+	windowsExecutables = []string{".exe", ".com", ".bat", ".cmd", ".ps1", ".vbs", ".vbe", ".js", ".jse", ".wsf", ".wsh", ".msc", ".msi", ".scr", ".hta", ".cpl", ".lnk", ".pif"}
+)
+
+var linkCmd = &cobra.Command{
+	Use:   "link [todo-id] [ref...]",
+	Short: "Attach URLs, files or code locations to a todo",
+	Long: `Attach references to a todo: URLs (https://...), local files
+(./notes.md) or code locations (main.go:42). Files are stored with their
+absolute path. Without references, the todo's links are listed with the
+numbers 'todo open' takes.
+
+--remove takes a link's number or the link itself and can be repeated.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		id := args[0]
+		remove, _ := cmd.Flags().GetStringArray("remove")
+
+		var refs []string
+		for _, arg := range args[1:] {
+			ref, err := normalizeLink(arg)
+			if err != nil {
+				fmt.Printf("%s%v%s\n", config.Red, err, config.Reset)
+				return
+			}
+			refs = append(refs, ref)
+		}
+
+		s, err := fs.Open()
+		if err != nil {
+			fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+		defer s.Close()
+
+		todo, err := s.ResolveTodo(id)
+		if err != nil {
+			reportTodoError(id, err)
+			return
+		}
+
+		if len(refs) == 0 && len(remove) == 0 {
+			if len(todo.Links) == 0 {
+				fmt.Printf("%sTodo [%d] has no links%s\n", config.Yellow, todo.Number, config.Reset)
+				return
+			}
+			fmt.Printf("%sLinks of todo [%s%d%s]:%s\n", config.Blue+config.Bold, config.Purple, todo.Number, config.Blue+config.Bold, config.Reset)
+			printLinks(todo.Links, "  ")
+			return
+		}
+
+		var removed []string
+		for _, value := range remove {
+			ref, err := pickLink(todo.Links, value)
+			if err != nil {
+				fmt.Printf("%s%v%s\n", config.Red, err, config.Reset)
+				return
+			}
+			removed = append(removed, ref)
+		}
+		todo.Links = slices.DeleteFunc(todo.Links, func(ref string) bool {
+			return slices.Contains(removed, ref)
+		})
+		for _, ref := range refs {
+			if !slices.Contains(todo.Links, ref) {
+				todo.Links = append(todo.Links, ref)
+			}
+		}
+		if len(todo.Links) == 0 {
+			todo.Links = nil
+		}
+		todo.UpdatedAt = time.Now()
+
+		if err = s.PutTodo(*todo); err != nil {
+			fmt.Printf("%sError saving config: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		fmt.Printf("%sUpdated links of todo [%s%d%s] (%d link(s))%s\n", config.Green,
+			config.Purple, todo.Number, config.Green, len(todo.Links), config.Reset)
+		printLinks(todo.Links, "  ")
+	},
+}
+
+var openCmd = &cobra.Command{
+	Use:   "open [todo-id] [n]",
+	Short: "Open a link of a todo",
+	Long: `Open the n-th link of a todo, as numbered by 'todo link <id>'. n can be
+left out when the todo has a single link.
+
+Links are handed to the command in the 'opener' setting, with {} replaced by
+the link or the link appended. Without one, URLs and files go to the
+system's default opener and code locations to $VISUAL or $EDITOR at that
+line. Only http, https and mailto URLs are opened, and executable files are
+refused.`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		id := args[0]
+
+		s, err := fs.Open()
+		if err != nil {
+			fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		todo, err := s.ResolveTodo(id)
+		// The store is not needed while the link is open.
+		s.Close()
+		if err != nil {
+			reportTodoError(id, err)
+			return
+		}
+
+		if len(todo.Links) == 0 {
+			fmt.Printf("%sTodo [%d] has no links, add one with 'todo link %d <ref>'%s\n", config.Yellow, todo.Number, todo.Number, config.Reset)
+			return
+		}
+
+		var ref string
+		if len(args) == 2 {
+			ref, err = pickLink(todo.Links, args[1])
+			if err != nil {
+				fmt.Printf("%s%v%s\n", config.Red, err, config.Reset)
+				return
+			}
+		} else if len(todo.Links) == 1 {
+			ref = todo.Links[0]
+		} else {
+			fmt.Printf("%sTodo [%d] has %d links, pick one with 'todo open %d <n>':%s\n", config.Yellow, todo.Number, len(todo.Links), todo.Number, config.Reset)
+			printLinks(todo.Links, "  ")
+			return
+		}
+
+		if err := openLink(ref); err != nil {
+			fmt.Printf("%sError opening '%s': %v%s\n", config.Red, ref, err, config.Reset)
+		}
+	},
+}
+
+// classifyLink tells URLs, files and path:line code locations apart. For
+// files and code locations it also returns the path and line.
+func classifyLink(ref string) (string, string, int) {
+	if schemePattern.MatchString(ref) || strings.HasPrefix(ref, "mailto:") {
+		return linkURL, "", 0
+	}
+	if m := codeLinkPattern.FindStringSubmatch(ref); m != nil {
+		line, _ := strconv.Atoi(m[2])
+		return linkCode, m[1], line
+	}
+	return linkFile, ref, 0
+}
+
+// normalizeLink makes file and code references absolute, so they can be
+// opened from any directory, and checks that the file exists.
+func normalizeLink(ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	kind, path, line := classifyLink(ref)
+	if kind == linkURL {
+		if err := checkLink(ref); err != nil {
+			return "", fmt.Errorf("cannot link '%s': %w", ref, err)
+		}
+		return ref, nil
+	}
+
+	if rest, ok := strings.CutPrefix(path, "~"+string(filepath.Separator)); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, rest)
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("cannot link '%s': %w", ref, err)
+	}
+
+	if kind == linkCode {
+		return fmt.Sprintf("%s:%d", path, line), nil
+	}
+	return path, nil
+}
+
+// pickLink finds a link by its number, counting from 1, or by its text.
+func pickLink(links []string, value string) (string, error) {
+	if n, err := strconv.Atoi(value); err == nil {
+		if n < 1 || n > len(links) {
+			return "", fmt.Errorf("no link number %d, the todo has %d link(s)", n, len(links))
+		}
+		return links[n-1], nil
+	}
+	if slices.Contains(links, value) {
+		return value, nil
+	}
+	return "", fmt.Errorf("the todo has no link '%s'", value)
+}
+
+func printLinks(links []string, indent string) {
+	for i, ref := range links {
+		kind, _, _ := classifyLink(ref)
+		fmt.Printf("%s%s%d.%s %s %s(%s)%s\n", indent, config.Cyan, i+1, config.Reset, ref, config.Cyan, kind, config.Reset)
+	}
+}
+
+func openLink(ref string) error {
+	if err := checkLink(ref); err != nil {
+		return err
+	}
+	kind, path, line := classifyLink(ref)
+
+	var command []string
+	switch {
+	case userSettings.Opener != "":
+		command = strings.Fields(userSettings.Opener)
+		if i := slices.Index(command, "{}"); i >= 0 {
+			command[i] = ref
+		} else {
+			command = append(command, ref)
+		}
+	case kind == linkCode:
+		command = append(strings.Fields(editorCommand()), fmt.Sprintf("+%d", line), path)
+	default:
+		command = append(systemOpener(), ref)
+	}
+	if len(command) == 0 {
+		return errors.New("no opener configured, set the opener setting")
+	}
+
+	opener := exec.Command(command[0], command[1:]...)
+	opener.Stdin = os.Stdin
+	opener.Stdout = os.Stdout
+	opener.Stderr = os.Stderr
+	return opener.Run()
+}
+
+// checkLink refuses links that an opener could take as a flag or turn into
+// running a program: URLs outside openSchemes, relative paths and
+// executable files. Links come from a store that may be shared, so they are
+// checked every time they are opened, not only when they are added.
+func checkLink(ref string) error {
+	if strings.HasPrefix(ref, "-") {
+		return fmt.Errorf("refusing to open a link that starts with '-'")
+	}
+
+	kind, path, _ := classifyLink(ref)
+	if kind == linkURL {
+		scheme, _, _ := strings.Cut(ref, ":")
+		if !slices.Contains(openSchemes, strings.ToLower(scheme)) {
+			return fmt.Errorf("refusing to open a %s: link, only %s are opened", scheme, strings.Join(openSchemes, ", "))
+		}
+		return nil
+	}
+
+	if !filepath.IsAbs(path) {
+		return fmt.Errorf("refusing to open a relative path, link the file again")
+	}
+	if kind == linkCode {
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+	if runtime.GOOS == "windows" {
+		ext := strings.ToLower(filepath.Ext(path))
+		pathExt := strings.Split(strings.ToLower(os.Getenv("PATHEXT")), ";")
+		if ext != "" && (slices.Contains(windowsExecutables, ext) || slices.Contains(pathExt, ext)) {
+			return fmt.Errorf("refusing to open an executable file")
+		}
+	} else if info.Mode()&0111 != 0 {
+		return fmt.Errorf("refusing to open an executable file")
+	}
+	return nil
+}
+
+func systemOpener() []string {
+	switch runtime.GOOS {
+	case "darwin":
+		return []string{"open"}
+	case "windows":
+		return []string{"rundll32", "url.dll,FileProtocolHandler"}
+	}
+	return []string{"xdg-open"}
+}
+
+// linkLabel is the short text shown for a link in listings: the host and
+// path of a URL, or the file name of a file.
+func linkLabel(ref string) string {
+	kind, path, line := classifyLink(ref)
+	label := filepath.Base(path)
+	switch kind {
+	case linkURL:
+		label = strings.TrimSuffix(schemePattern.ReplaceAllString(ref, ""), "/")
+		label = strings.TrimPrefix(label, "www.")
+		if runes := []rune(label); len(runes) > 40 {
+			label = string(runes[:37]) + "..."
+		}
+	case linkCode:
+		label = fmt.Sprintf("%s:%d", label, line)
+	}
+	return label
+}
+
+// hyperlink wraps text in an OSC 8 escape sequence pointing at a link, so
+// terminals that support it make the text clickable.
+func hyperlink(ref, text string) string {
+	kind, path, _ := classifyLink(ref)
+	target := ref
+	if kind != linkURL {
+		target = (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+	}
+	return "\033]8;;" + target + "\033\\" + text + "\033]8;;\033\\"
+}
+
+// useHyperlinks decides whether to print OSC 8 hyperlinks. Terminals that
+// do not know them may print the escapes as garbage, so in auto mode they
+// are only used on terminals known to support them.
+func useHyperlinks(mode string) bool {
+	switch mode {
+	case fs.ColorAlways:
+		return true
+	case fs.ColorNever:
+		return false
+	}
+
+	if !isTerminal(os.Stdout) || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	switch os.Getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "ghostty", "Hyper", "Tabby":
+		return true
+	}
+	if vte, err := strconv.Atoi(os.Getenv("VTE_VERSION")); err == nil && vte >= 5000 {
+		return true
+	}
+	for _, env := range []string{"WT_SESSION", "KONSOLE_VERSION", "KITTY_WINDOW_ID", "DOMTERM"} {
+		if os.Getenv(env) != "" {
+			return true
+		}
+	}
+	switch os.Getenv("TERM") {
+	case "xterm-kitty", "alacritty", "foot", "xterm-ghostty", "wezterm":
+		return true
+	}
+	return false
+}
+
+func init() {
+	linkCmd.Flags().StringArray("remove", nil, "Remove a link by number or by value")
+}
@@ -2,8 +2,10 @@ package cmd
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/dorukozerr/todo-cli/internal/config"
 	"github.com/dorukozerr/todo-cli/internal/fs"
@@ -19,54 +21,238 @@ var listCmd = &cobra.Command{
 - Default: Shows incomplete todos from active group
 - --all: Shows all todos from active group
 - --all-groups: Shows incomplete todos from all groups
-- --all --all-groups: Shows all todos from all groups`,
+- --all --all-groups: Shows all todos from all groups
+- --status x: Only todos in state x, repeatable, e.g. --status in_progress
+- --waiting: Only todos snoozed until a later date, which are otherwise
+  hidden unless --all is given
+- --archived: Shows archived todos instead, from the active group or all groups
+- --sort urgency|age|due|score|<field>: Within each state (see 'todo
+  workflow'), order by urgency (default), oldest first, soonest due, highest
+  score (see 'todo explain') or a custom field
+- --tag x --tag -y: Only todos tagged x and not tagged y, repeatable
+- --ready: Only incomplete todos that are not blocked by another todo
+- --overdue: Only incomplete todos past their due date
+- --due-before <date>: Only todos due before a date, e.g. --due-before fri
+- --where <field><op><value>: Only todos whose custom field matches, with
+  =, !=, <, <=, > or >=, e.g. --where env=prod --where story_points>=3;
+  'env=' matches todos without the field`,
 	Run: func(cmd *cobra.Command, args []string) {
 		showAll, _ := cmd.Flags().GetBool("all")
 		allGroups, _ := cmd.Flags().GetBool("all-groups")
+		archived, _ := cmd.Flags().GetBool("archived")
+		sortBy, _ := cmd.Flags().GetString("sort")
+		overdue, _ := cmd.Flags().GetBool("overdue")
+		dueBefore, _ := cmd.Flags().GetString("due-before")
+		ready, _ := cmd.Flags().GetBool("ready")
+		tagArgs, _ := cmd.Flags().GetStringArray("tag")
+		statusArgs, _ := cmd.Flags().GetStringArray("status")
+		waiting, _ := cmd.Flags().GetBool("waiting")
+		whereArgs, _ := cmd.Flags().GetStringArray("where")
 
-		c, err := fs.GetConfig()
+		includeTags, excludeTags, err := splitTagArgs(tagArgs)
+		if err != nil {
+			fmt.Printf("%s%v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		now := time.Now()
+		var dueFilter func(todo types.Todo) bool
+		switch {
+		case overdue:
+			dueFilter = func(todo types.Todo) bool {
+				return !todo.Completed && todo.Due != nil && utils.IsOverdue(*todo.Due, now)
+			}
+		case dueBefore != "":
+			cutoff, err := utils.ParseDue(dueBefore, now)
+			if err != nil {
+				fmt.Printf("%s%v%s\n", config.Red, err, config.Reset)
+				return
+			}
+			dueFilter = func(todo types.Todo) bool {
+				return todo.Due != nil && todo.Due.Before(cutoff)
+			}
+		}
+
+		s, err := fs.Open()
+		if err != nil {
+			fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+		defer s.Close()
+
+		if archived {
+			listArchived(s, allGroups)
+			return
+		}
+
+		c, err := s.Load()
 		if err != nil {
 			fmt.Printf("%sError loading config: %v%s\n", config.Red, err, config.Reset)
 			return
 		}
 
+		sortField, isField := utils.FindField(c.Fields, sortBy)
+		if sortBy != "urgency" && sortBy != "age" && sortBy != "due" && sortBy != "score" && !isField {
+			fmt.Printf("%s--sort must be urgency, age, due, score or a custom field%s\n", config.Red, config.Reset)
+			return
+		}
+
+		var fieldFilters []fieldFilter
+		for _, expr := range whereArgs {
+			filter, err := parseWhere(c.Fields, expr, now)
+			if err != nil {
+				fmt.Printf("%s%v%s\n", config.Red, err, config.Reset)
+				return
+			}
+			fieldFilters = append(fieldFilters, filter)
+		}
+
 		if len(c.Todos) == 0 {
 			fmt.Printf("%sNo todos found%s\n", config.Yellow, config.Reset)
 			return
 		}
 
-		filteredTodos := filterTodos(c.Todos, c.ActiveGroup, showAll, allGroups)
+		w := utils.StoreWorkflow(c)
+		var statuses []string
+		for _, arg := range statusArgs {
+			status := utils.NormalizeStatus(arg)
+			if _, ok := utils.FindState(w, status); !ok {
+				fmt.Printf("%sUnknown state '%s', the workflow has: %s%s\n", config.Red, arg, stateNames(w), config.Reset)
+				return
+			}
+			statuses = append(statuses, status)
+		}
+
+		filteredTodos := filterTodos(c.Todos, todoFilter{
+			activeGroup: c.ActiveGroup,
+			showAll:     showAll,
+			allGroups:   allGroups,
+			waiting:     waiting,
+			statuses:    statuses,
+			now:         now,
+		})
+		if len(tagArgs) > 0 {
+			var tagged []types.Todo
+			for _, todo := range filteredTodos {
+				if matchTags(todo, includeTags, excludeTags) {
+					tagged = append(tagged, todo)
+				}
+			}
+			filteredTodos = tagged
+		}
+		if ready {
+			blocked := blockedTodos(c)
+			var unblocked []types.Todo
+			for _, todo := range filteredTodos {
+				if !todo.Completed && !blocked[todo.ID] {
+					unblocked = append(unblocked, todo)
+				}
+			}
+			filteredTodos = unblocked
+		}
+		if len(fieldFilters) > 0 {
+			var matching []types.Todo
+			for _, todo := range filteredTodos {
+				if !slices.ContainsFunc(fieldFilters, func(f fieldFilter) bool { return !f.match(todo) }) {
+					matching = append(matching, todo)
+				}
+			}
+			filteredTodos = matching
+		}
+		if dueFilter != nil {
+			var matching []types.Todo
+			for _, todo := range filteredTodos {
+				if dueFilter(todo) {
+					matching = append(matching, todo)
+				}
+			}
+			filteredTodos = matching
+		}
 
 		if len(filteredTodos) == 0 {
-			displayEmptyMessage(showAll, allGroups, c.ActiveGroup)
+			displayEmptyMessage(showAll, allGroups, waiting, c.ActiveGroup, statuses)
 			return
 		}
 
-		sort.Slice(filteredTodos, func(i, j int) bool {
-			if filteredTodos[i].Completed != filteredTodos[j].Completed {
-				return !filteredTodos[i].Completed
+		var scores map[string]float64
+		if sortBy == "score" {
+			scores = todoScores(c, filteredTodos, now)
+		}
+
+		sort.SliceStable(filteredTodos, func(i, j int) bool {
+			if a, b := utils.StateRank(w, filteredTodos[i].Status), utils.StateRank(w, filteredTodos[j].Status); a != b {
+				return a < b
+			}
+			if isField {
+				a, aok := filteredTodos[i].Fields[sortField.Name]
+				b, bok := filteredTodos[j].Fields[sortField.Name]
+				if !aok || !bok {
+					return aok
+				}
+				return utils.CompareFieldValues(sortField, a, b) < 0
+			}
+			switch sortBy {
+			case "age":
+				return filteredTodos[i].CreatedAt.Before(filteredTodos[j].CreatedAt)
+			case "due":
+				a, b := filteredTodos[i].Due, filteredTodos[j].Due
+				if a == nil || b == nil {
+					return a != nil
+				}
+				return a.Before(*b)
+			case "score":
+				return scores[filteredTodos[i].ID] > scores[filteredTodos[j].ID]
 			}
 			return filteredTodos[i].Urgency > filteredTodos[j].Urgency
 		})
 
-		displayHeader(showAll, allGroups, c.ActiveGroup)
+		location, err := fs.LocateStore()
+		if err != nil {
+			fmt.Printf("%sError locating store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		displayHeader(showAll, allGroups, waiting, c.ActiveGroup, statuses, location)
 
 		if allGroups {
-			displayTodosByGroup(filteredTodos)
+			displayTodosByGroup(filteredTodos, c, scores)
 		} else {
-			displayTodosList(filteredTodos)
+			displayTodosList(filteredTodos, c, scores)
 		}
 	},
 }
 
-func filterTodos(todos []types.Todo, activeGroup string, showAll, allGroups bool) []types.Todo {
+// todoFilter holds the list flags that decide which todos are shown at all.
+type todoFilter struct {
+	activeGroup string
+	showAll     bool
+	allGroups   bool
+	waiting     bool
+	statuses    []string
+	now         time.Time
+}
+
+// filterTodos keeps the todos of the active group, or of all groups, that
+// are open and not snoozed or, with showAll, every todo. Listing statuses
+// picks the states to show instead, and waiting only keeps snoozed todos.
+func filterTodos(todos []types.Todo, f todoFilter) []types.Todo {
 	var filtered []types.Todo
 
 	for _, todo := range todos {
-		if !showAll && todo.Completed {
+		if len(f.statuses) > 0 {
+			if !slices.Contains(f.statuses, todo.Status) {
+				continue
+			}
+		} else if !f.showAll && todo.Completed {
+			continue
+		}
+		snoozed := isWaiting(todo.Wait, f.now)
+		if f.waiting && !snoozed {
+			continue
+		} else if !f.waiting && snoozed && !f.showAll {
 			continue
 		}
-		if !allGroups && todo.Group != activeGroup {
+		if !f.allGroups && todo.Group != f.activeGroup {
 			continue
 		}
 		filtered = append(filtered, todo)
@@ -75,9 +261,63 @@ func filterTodos(todos []types.Todo, activeGroup string, showAll, allGroups bool
 	return filtered
 }
 
-func displayEmptyMessage(showAll, allGroups bool, activeGroup string) {
+func listArchived(s *fs.Handle, allGroups bool) {
+	c, err := s.Load()
+	if err != nil {
+		fmt.Printf("%sError loading config: %v%s\n", config.Red, err, config.Reset)
+		return
+	}
+
+	archive, err := s.LoadArchive()
+	if err != nil {
+		fmt.Printf("%sError loading archive: %v%s\n", config.Red, err, config.Reset)
+		return
+	}
+
+	var entries []types.ArchivedTodo
+	for _, entry := range archive.Todos {
+		if allGroups || entry.Group == c.ActiveGroup {
+			entries = append(entries, entry)
+		}
+	}
+
+	scope := "from all groups"
+	if !allGroups {
+		scope = fmt.Sprintf("from group '%s'", displayGroupName(c.ActiveGroup))
+	}
+
+	if len(entries) == 0 {
+		fmt.Printf("%sNo archived todos found %s%s\n", config.Yellow, scope, config.Reset)
+		return
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ArchivedAt.After(entries[j].ArchivedAt)
+	})
+
+	fmt.Printf("\n%sArchived todos %s:%s\n", config.Blue+config.Bold, scope, config.Reset)
+	fmt.Println(strings.Repeat("=", 40))
+	for _, entry := range entries {
+		urgencyText, urgencyColor := utils.GetUrgencyDisplay(entry.Urgency)
+		groupText := ""
+		if allGroups {
+			groupText = fmt.Sprintf(" %s(%s)%s", config.Yellow, displayGroupName(entry.Group), config.Reset)
+		}
+		fmt.Printf("%s%s%s [%s%d%s] %s%s%s %s%s\n",
+			config.Cyan, utils.FormatDate(entry.ArchivedAt, userSettings.DateFormat), config.Reset,
+			config.Purple, entry.Number, config.Reset,
+			urgencyColor, urgencyText, config.Reset,
+			entry.Task, groupText)
+	}
+}
+
+func displayEmptyMessage(showAll, allGroups, waiting bool, activeGroup string, statuses []string) {
 	status := "incomplete todos"
-	if showAll {
+	if waiting {
+		status = "snoozed todos"
+	} else if len(statuses) > 0 {
+		status = "todos in state " + strings.Join(statuses, ", ")
+	} else if showAll {
 		status = "todos"
 	}
 
@@ -92,9 +332,13 @@ func displayEmptyMessage(showAll, allGroups bool, activeGroup string) {
 	}
 }
 
-func displayHeader(showAll, allGroups bool, activeGroup string) {
+func displayHeader(showAll, allGroups, waiting bool, activeGroup string, statuses []string, location fs.StoreLocation) {
 	status := "Incomplete todos"
-	if showAll {
+	if waiting {
+		status = "Snoozed todos"
+	} else if len(statuses) > 0 {
+		status = "Todos in state " + strings.Join(statuses, ", ")
+	} else if showAll {
 		status = "All todos"
 	}
 
@@ -108,10 +352,11 @@ func displayHeader(showAll, allGroups bool, activeGroup string) {
 	}
 
 	fmt.Printf("\n%s%s %s:%s\n", config.Blue+config.Bold, status, scope, config.Reset)
+	fmt.Printf("%sStore:%s %s (%s)\n", config.Cyan, config.Reset, location.Path, location.Source)
 	fmt.Println(strings.Repeat("=", 40))
 }
 
-func displayTodosByGroup(todos []types.Todo) {
+func displayTodosByGroup(todos []types.Todo, c *types.Config, scores map[string]float64) {
 	todoGroups := make(map[string][]types.Todo)
 	for _, todo := range todos {
 		groupName := todo.Group
@@ -130,29 +375,122 @@ func displayTodosByGroup(todos []types.Todo) {
 
 		fmt.Printf("\n%s%s%s\n", config.Cyan+config.Bold, groupName, config.Reset)
 		fmt.Println(strings.Repeat("-", 20))
-		displayTodosList(groupTodos)
+		displayTodosList(groupTodos, c, scores)
 	}
 }
 
-func displayTodosList(todos []types.Todo) {
-	for _, todo := range todos {
-		status := "[ ]"
-		statusColor := config.Yellow
-		if todo.Completed {
-			status = "[x]"
-			statusColor = config.Green
+// displayTodosList prints todos as a tree with subtasks under their parent.
+// Progress counts come from the whole store, so hidden completed subtasks
+// still count. Scores are shown for the todos in scores, if any.
+func displayTodosList(todos []types.Todo, c *types.Config, scores map[string]float64) {
+	now := time.Now()
+	progress := subtaskProgress(c.Todos)
+	w := utils.StoreWorkflow(c)
+	hyperlinks := useHyperlinks(userSettings.Hyperlinks)
+	for _, row := range todoTree(todos) {
+		todo := row.todo
+		indent := strings.Repeat("  ", row.depth)
+
+		status := statusMark(w, todo.Status)
+		state, _ := utils.FindState(w, todo.Status)
+		color := statusColor(w, state)
+
+		var annotations []annotation
+		if todo.Notes != "" {
+			annotations = append(annotations, annotation{config.Cyan, "[notes]"})
+		}
+		if len(todo.Tags) > 0 {
+			annotations = append(annotations, annotation{config.Blue, formatTags(todo.Tags)})
+		}
+		for _, pair := range formatFields(c.Fields, todo.Fields) {
+			annotations = append(annotations, annotation{config.Purple, pair})
+		}
+		for _, ref := range todo.Links {
+			if hyperlinks {
+				annotations = append(annotations, annotation{config.Cyan + config.Underline, hyperlink(ref, linkLabel(ref))})
+			} else {
+				annotations = append(annotations, annotation{config.Cyan, "<" + linkLabel(ref) + ">"})
+			}
+		}
+		if counts, ok := progress[todo.ID]; ok {
+			annotations = append(annotations, annotation{config.Green, fmt.Sprintf("(%d/%d)", counts[0], counts[1])})
+		}
+		if timerRunning(todo) {
+			annotations = append(annotations, annotation{config.Green + config.Bold, "[running " + utils.FormatDuration(totalTime(todo, now)) + "]"})
+		} else if tracked := totalTime(todo, now); tracked > 0 {
+			annotations = append(annotations, annotation{config.Cyan, "[" + utils.FormatDuration(tracked) + "]"})
+		}
+		if todo.Due != nil {
+			dueColor := config.Blue
+			if !todo.Completed && utils.IsOverdue(*todo.Due, now) {
+				dueColor = config.Red + config.Bold
+			} else if !todo.Completed && utils.IsDueToday(*todo.Due, now) {
+				dueColor = config.Yellow + config.Bold
+			}
+			annotations = append(annotations, annotation{dueColor, "due " + utils.FormatDue(*todo.Due, userSettings.DateFormat, now)})
+		}
+		if isWaiting(todo.Wait, now) {
+			annotations = append(annotations, annotation{config.Cyan, "(snoozed until " + utils.FormatDue(*todo.Wait, userSettings.DateFormat, now) + ")"})
+		}
+		if score, ok := scores[todo.ID]; ok {
+			annotations = append(annotations, annotation{config.Purple, fmt.Sprintf("score %.1f", score)})
 		}
 
 		urgencyText, urgencyColor := utils.GetUrgencyDisplay(todo.Urgency)
-		fmt.Printf("%s%s%s [%s%s%s] %s%s%s %s\n",
-			statusColor, status, config.Reset,
-			config.Purple, todo.ID, config.Reset,
+
+		if blockedBy := blockers(c, todo); len(blockedBy) > 0 && !todo.Completed {
+			annotations = append(annotations, annotation{"", fmt.Sprintf("(blocked by %s)", formatTodoRefs(c, blockedBy))})
+			fmt.Printf("%s%s%s [%d] %s %s%s%s\n",
+				indent, config.Dim,
+				status, todo.Number, urgencyText, todo.Task, formatAnnotations(annotations, false),
+				config.Reset)
+			continue
+		}
+
+		age := utils.FormatAge(todo.CreatedAt, now)
+		if todo.Completed && todo.CompletedAt != nil {
+			age = todo.Status + " " + utils.FormatAge(*todo.CompletedAt, now)
+		}
+		annotations = append(annotations, annotation{config.Cyan, "(" + age + ")"})
+
+		fmt.Printf("%s%s%s%s [%s%d%s] %s%s%s %s%s\n",
+			indent,
+			color, status, config.Reset,
+			config.Purple, todo.Number, config.Reset,
 			urgencyColor, urgencyText, config.Reset,
-			todo.Task)
+			todo.Task, formatAnnotations(annotations, true))
+	}
+}
+
+// annotation is one note printed after a todo's task in a listing.
+type annotation struct {
+	color string
+	text  string
+}
+
+func formatAnnotations(annotations []annotation, colored bool) string {
+	var b strings.Builder
+	for _, a := range annotations {
+		b.WriteString(" ")
+		if colored && a.color != "" {
+			b.WriteString(a.color + a.text + config.Reset)
+		} else {
+			b.WriteString(a.text)
+		}
 	}
+	return b.String()
 }
 
 func init() {
 	listCmd.Flags().BoolP("all", "a", false, "Show completed and incomplete todos")
 	listCmd.Flags().Bool("all-groups", false, "Show todos from all groups")
+	listCmd.Flags().Bool("archived", false, "Show archived todos")
+	listCmd.Flags().StringArray("status", nil, "Show only todos in this state, can be repeated")
+	listCmd.Flags().Bool("waiting", false, "Show only todos snoozed until a later date")
+	listCmd.Flags().String("sort", "urgency", "Sort by urgency, age, due, score or a custom field")
+	listCmd.Flags().StringArray("tag", nil, "Show only todos with this tag, or without it when prefixed with '-'")
+	listCmd.Flags().Bool("ready", false, "Show only todos that are not blocked")
+	listCmd.Flags().Bool("overdue", false, "Show only incomplete todos past their due date")
+	listCmd.Flags().String("due-before", "", "Show only todos due before this date")
+	listCmd.Flags().StringArray("where", nil, "Show only todos whose custom field matches, e.g. env=prod or story_points>=3")
 }
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dorukozerr/todo-cli/internal/config"
+	"github.com/dorukozerr/todo-cli/internal/fs"
+	"github.com/spf13/cobra"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade the todo store to the current schema",
+	Long: `Upgrade the todo store to the current schema version.
+
+Stores are migrated automatically when they are loaded, and the original
+file is backed up first. Use --check to see what would change without
+writing anything.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		check, _ := cmd.Flags().GetBool("check")
+
+		report, err := fs.Migrate(check)
+		if err != nil {
+			fmt.Printf("%sError migrating store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		if len(report.Steps) == 0 {
+			fmt.Printf("%sStore is up to date (schema version %d)%s\n", config.Green, report.To, config.Reset)
+			return
+		}
+
+		if check {
+			fmt.Printf("%sStore %s is at schema version %d and would be migrated to %d:%s\n",
+				config.Yellow, report.Path, report.From, report.To, config.Reset)
+		} else {
+			fmt.Printf("%sMigrated store %s from schema version %d to %d:%s\n",
+				config.Green, report.Path, report.From, report.To, config.Reset)
+		}
+
+		for _, step := range report.Steps {
+			fmt.Printf("  %sv%d%s %s (%d changes)\n", config.Purple, step.Version, config.Reset, step.Description, len(step.Changes))
+			for _, change := range step.Changes {
+				fmt.Printf("    - %s\n", change)
+			}
+		}
+
+		if report.Backup != "" {
+			fmt.Printf("%sBackup:%s %s\n", config.Cyan, config.Reset, report.Backup)
+		}
+	},
+}
+
+func init() {
+	migrateCmd.Flags().Bool("check", false, "Report what would change without writing")
+}
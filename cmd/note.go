@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dorukozerr/todo-cli/internal/config"
+	"github.com/dorukozerr/todo-cli/internal/fs"
+	"github.com/dorukozerr/todo-cli/internal/types"
+	"github.com/spf13/cobra"
+)
+
+var noteCmd = &cobra.Command{
+	Use:   "note [todo-id]",
+	Short: "Edit the notes of a todo in $EDITOR",
+	Long: `Open $VISUAL or $EDITOR on the notes of a todo and save the result.
+Saving an empty file removes the notes. Notes are shown by 'todo show'.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ref := args[0]
+
+		todo, err := readTodo(ref)
+		if err != nil {
+			reportTodoError(ref, err)
+			return
+		}
+		id, number, original := todo.ID, todo.Number, todo.Notes
+
+		// The store is not locked while the editor is open, so other
+		// commands keep working in the meantime.
+		edited, err := editText(original, "todo-note-*.md")
+		if err != nil {
+			fmt.Printf("%sError running editor: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+		edited = strings.TrimRight(edited, " \t\r\n")
+
+		if edited == original {
+			fmt.Printf("%sNotes of todo [%d] unchanged%s\n", config.Yellow, number, config.Reset)
+			return
+		}
+
+		s, err := fs.Open()
+		if err != nil {
+			fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+		defer s.Close()
+
+		todo, err = s.GetTodo(id)
+		if err != nil {
+			fmt.Printf("%sError loading config: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		if todo.Notes != original {
+			fmt.Printf("%sThe notes of todo [%d] were changed while you were editing them, nothing was saved. Your version:%s\n\n%s\n",
+				config.Red, number, config.Reset, edited)
+			return
+		}
+
+		todo.Notes = edited
+		todo.UpdatedAt = time.Now()
+		if err = s.PutTodo(*todo); err != nil {
+			fmt.Printf("%sError saving config: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		if edited == "" {
+			fmt.Printf("%sRemoved the notes of todo [%s%d%s]%s\n", config.Green, config.Purple, number, config.Green, config.Reset)
+			return
+		}
+		fmt.Printf("%sSaved the notes of todo [%s%d%s] (%d lines)%s\n", config.Green,
+			config.Purple, number, config.Green,
+			strings.Count(edited, "\n")+1, config.Reset)
+	},
+}
+
+func readTodo(ref string) (*types.Todo, error) {
+	s, err := fs.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer s.Close()
+
+	return s.ResolveTodo(ref)
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// readPassphrase prompts on stderr and reads a line from stdin, hiding the
+// input when stdin is a terminal.
+func readPassphrase(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+
+	var line string
+	if isTerminal(os.Stdin) {
+		data, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("reading passphrase: %w", err)
+		}
+		line = string(data)
+	} else {
+		var err error
+		line, err = bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return "", errors.New("no passphrase given")
+		}
+	}
+
+	passphrase := strings.TrimRight(line, "\r\n")
+	if passphrase == "" {
+		return "", errors.New("passphrase cannot be empty")
+	}
+	return passphrase, nil
+}
+
+func promptStorePassphrase(configPath string) (string, error) {
+	if !isTerminal(os.Stdin) {
+		return "", fmt.Errorf("store %s is encrypted, set $TODO_CLI_PASSPHRASE", configPath)
+	}
+	return readPassphrase(fmt.Sprintf("Passphrase for %s: ", configPath))
+}
+
+// isTerminal reports whether f is a terminal. A character device is not
+// enough: /dev/null is one too.
+func isTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dorukozerr/todo-cli/internal/config"
+	"github.com/dorukozerr/todo-cli/internal/fs"
+	"github.com/dorukozerr/todo-cli/internal/types"
+	"github.com/dorukozerr/todo-cli/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var recurCmd = &cobra.Command{
+	Use:   "recur",
+	Short: "Manage recurring todos",
+	Long: `Manage recurring todos:
+- recur list: Show every recurring todo with its rule and next due date
+- recur stop <id>: Stop a todo from repeating
+
+Recurring todos are created with 'todo add --every <rule>'. Completing one
+creates the next instance with a new ID and the due date moved forward.`,
+}
+
+var recurListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recurring todos",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		s, err := fs.Open()
+		if err != nil {
+			fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+		defer s.Close()
+
+		c, err := s.Load()
+		if err != nil {
+			fmt.Printf("%sError loading config: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		var recurring []types.Todo
+		for _, todo := range c.Todos {
+			if todo.Recur != "" && !todo.Completed {
+				recurring = append(recurring, todo)
+			}
+		}
+
+		if len(recurring) == 0 {
+			fmt.Printf("%sNo recurring todos%s\n", config.Yellow, config.Reset)
+			return
+		}
+
+		sort.Slice(recurring, func(i, j int) bool {
+			a, b := recurring[i].Due, recurring[j].Due
+			if a == nil || b == nil {
+				return a != nil
+			}
+			return a.Before(*b)
+		})
+
+		now := time.Now()
+		fmt.Printf("\n%sRecurring todos (%d total):%s\n", config.Blue+config.Bold, len(recurring), config.Reset)
+		fmt.Println("========================================")
+		for _, todo := range recurring {
+			dueText := "no due date"
+			if todo.Due != nil {
+				dueText = "next due " + utils.FormatDue(*todo.Due, userSettings.DateFormat, now)
+			}
+			fmt.Printf("[%s%d%s] %s %s(%s, %s)%s %s%s%s\n",
+				config.Purple, todo.Number, config.Reset,
+				todo.Task,
+				config.Cyan, todo.Recur, dueText, config.Reset,
+				config.Yellow, displayGroupName(todo.Group), config.Reset)
+		}
+	},
+}
+
+var recurStopCmd = &cobra.Command{
+	Use:   "stop [todo-id]",
+	Short: "Stop a todo from repeating",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		id := args[0]
+
+		s, err := fs.Open()
+		if err != nil {
+			fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+		defer s.Close()
+
+		todo, err := s.ResolveTodo(id)
+		if err != nil {
+			reportTodoError(id, err)
+			return
+		}
+
+		if todo.Recur == "" {
+			fmt.Printf("%sTodo [%d] does not repeat%s\n", config.Yellow, todo.Number, config.Reset)
+			return
+		}
+
+		todo.Recur = ""
+		todo.UpdatedAt = time.Now()
+		if err = s.PutTodo(*todo); err != nil {
+			fmt.Printf("%sError saving config: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		fmt.Printf("%sTodo [%s%d%s] no longer repeats: %s%s%s\n", config.Green,
+			config.Purple, todo.Number, config.Green,
+			config.Bold, todo.Task, config.Reset)
+	},
+}
+
+// nextRecurrence builds the instance that follows todo. Its due date moves
+// forward by the rule from the previous due date until it is in the future,
+// so occurrences missed while the todo was open are skipped.
+func nextRecurrence(c types.Config, todo types.Todo, now time.Time) (types.Todo, error) {
+	rule, err := utils.ParseRecurrence(todo.Recur)
+	if err != nil {
+		return types.Todo{}, fmt.Errorf("todo [%d]: %w", todo.Number, err)
+	}
+
+	due := now
+	if todo.Due != nil {
+		due = *todo.Due
+	}
+	// Rules written before month rules were anchored get pinned to the
+	// current due date here.
+	rule = rule.Anchored(due)
+	due = rule.Next(due)
+	for !due.After(now) {
+		due = rule.Next(due)
+	}
+
+	next := todo
+	next.ID = utils.GenerateUUID()
+	next.Number = utils.NextTodoNumber(c)
+	next.Status = utils.StoreWorkflow(&c).Initial
+	next.Completed = false
+	next.CompletedAt = nil
+	next.CreatedAt = now
+	next.UpdatedAt = now
+	next.Due = &due
+	next.Recur = rule.String()
+	next.Wait = nil
+	next.TimeLog = nil
+
+	return next, nil
+}
+
+func init() {
+	recurCmd.AddCommand(recurListCmd)
+	recurCmd.AddCommand(recurStopCmd)
+}
@@ -2,28 +2,96 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
+	"github.com/dorukozerr/todo-cli/internal/config"
+	"github.com/dorukozerr/todo-cli/internal/fs"
 	"github.com/spf13/cobra"
 )
 
+var userSettings = fs.DefaultSettings()
+
 var RootCmd = &cobra.Command{
 	Use:   "todo",
 	Short: "A simple todo CLI",
 	Long:  "A command-line todo application with group management and priority levels",
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		file, _ := cmd.Flags().GetString("file")
+		fs.SetStoreFile(file)
+
+		settings, err := fs.GetSettings()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v, using default settings\n", err)
+			settings = fs.DefaultSettings()
+		}
+		userSettings = settings
+
+		if !useColors(settings.Color) {
+			config.DisableColors()
+		}
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println("Todo CLI - Use 'todo --help' for available commands")
 	},
 }
 
+func useColors(mode string) bool {
+	switch mode {
+	case fs.ColorAlways:
+		return true
+	case fs.ColorNever:
+		return false
+	}
+
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
 func init() {
 	RootCmd.SilenceUsage = true
 	RootCmd.SilenceErrors = true
 
+	RootCmd.PersistentFlags().String("file", "", "Use this todo store file (overrides $TODO_CLI_FILE and $TODO_CLI_HOME)")
+
 	RootCmd.AddCommand(addCmd)
 	RootCmd.AddCommand(completeCmd)
 	RootCmd.AddCommand(incompleteCmd)
+	RootCmd.AddCommand(statusCmd)
+	RootCmd.AddCommand(workflowCmd)
+	RootCmd.AddCommand(snoozeCmd)
+	RootCmd.AddCommand(linkCmd)
+	RootCmd.AddCommand(openCmd)
+	RootCmd.AddCommand(exportCmd)
 	RootCmd.AddCommand(updateCmd)
 	RootCmd.AddCommand(deleteCmd)
 	RootCmd.AddCommand(listCmd)
 	RootCmd.AddCommand(groupCmd)
+	RootCmd.AddCommand(migrateCmd)
+	RootCmd.AddCommand(initCmd)
+	RootCmd.AddCommand(backupCmd)
+	RootCmd.AddCommand(restoreCmd)
+	RootCmd.AddCommand(undoCmd)
+	RootCmd.AddCommand(redoCmd)
+	RootCmd.AddCommand(configCmd)
+	RootCmd.AddCommand(encryptCmd)
+	RootCmd.AddCommand(decryptCmd)
+	RootCmd.AddCommand(archiveCmd)
+	RootCmd.AddCommand(unarchiveCmd)
+	RootCmd.AddCommand(doctorCmd)
+	RootCmd.AddCommand(recurCmd)
+	RootCmd.AddCommand(dependCmd)
+	RootCmd.AddCommand(tagCmd)
+	RootCmd.AddCommand(tagsCmd)
+	RootCmd.AddCommand(noteCmd)
+	RootCmd.AddCommand(showCmd)
+	RootCmd.AddCommand(explainCmd)
+	RootCmd.AddCommand(startCmd)
+	RootCmd.AddCommand(stopCmd)
+	RootCmd.AddCommand(logCmd)
+	RootCmd.AddCommand(timeCmd)
+
+	fs.PassphrasePrompt = promptStorePassphrase
 }
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/dorukozerr/todo-cli/internal/config"
+	"github.com/dorukozerr/todo-cli/internal/fs"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage personal settings",
+	Long: `Manage personal settings:
+- config list: Show every setting with its value
+- config get <key>: Show one setting
+- config set <key> <value>: Change a setting
+- config edit: Edit settings.json in $EDITOR
+- config field: Declare custom fields for todos
+
+Settings are kept per user, apart from the todo store, so a shared store
+does not share anyone's preferences.`,
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all settings",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		settings, err := fs.GetSettings()
+		if err != nil {
+			fmt.Printf("%sError loading settings: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		path, _ := fs.SettingsPath()
+		defaults := fs.DefaultSettings()
+
+		fmt.Printf("\n%sSettings (%s):%s\n", config.Blue+config.Bold, path, config.Reset)
+		fmt.Println("========================================")
+		for _, setting := range fs.Settings() {
+			value := setting.Get(settings)
+			defaultValue := setting.Get(defaults)
+			marker := ""
+			if value != defaultValue {
+				marker = config.Yellow + " *" + config.Reset
+			}
+			if defaultValue == "" {
+				defaultValue = "empty"
+			}
+			fmt.Printf("%s%s%s = %s%s%s%s\n", config.Cyan, setting.Key, config.Reset, config.Bold, value, config.Reset, marker)
+			fmt.Printf("  %s (%s, default %s)\n", setting.Description, setting.Type, defaultValue)
+		}
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get [key]",
+	Short: "Show a setting",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		setting, err := fs.LookupSetting(args[0])
+		if err != nil {
+			fmt.Printf("%s%v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		settings, err := fs.GetSettings()
+		if err != nil {
+			fmt.Printf("%sError loading settings: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		fmt.Println(setting.Get(settings))
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set [key] [value]",
+	Short: "Change a setting",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := fs.SetSetting(args[0], args[1]); err != nil {
+			fmt.Printf("%s%v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		fmt.Printf("%sSet %s%s%s to %s%s%s\n", config.Green, config.Cyan, args[0], config.Green, config.Bold, args[1], config.Reset)
+	},
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit settings.json in $EDITOR",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		settings, err := fs.GetSettings()
+		if err != nil {
+			fmt.Printf("%sError loading settings: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		path, err := fs.SettingsPath()
+		if err != nil {
+			fmt.Printf("%sError locating settings: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		content, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			content, err = json.MarshalIndent(settings, "", "  ")
+		}
+		if err != nil {
+			fmt.Printf("%sError reading settings: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		edited, err := editText(string(content), "todo-settings-*.json")
+		if err != nil {
+			fmt.Printf("%sError running editor: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		parsed, err := fs.ParseSettings([]byte(edited))
+		if err != nil {
+			fmt.Printf("%sSettings not saved: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		if err = fs.SaveSettings(parsed); err != nil {
+			fmt.Printf("%sError saving settings: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		fmt.Printf("%sSaved settings to %s%s\n", config.Green, path, config.Reset)
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configEditCmd)
+	configCmd.AddCommand(configFieldCmd)
+}
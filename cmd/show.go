@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dorukozerr/todo-cli/internal/config"
+	"github.com/dorukozerr/todo-cli/internal/fs"
+	"github.com/dorukozerr/todo-cli/internal/types"
+	"github.com/dorukozerr/todo-cli/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var showCmd = &cobra.Command{
+	Use:   "show [todo-id]",
+	Short: "Show every detail of a todo, including its notes",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ref := args[0]
+
+		s, err := fs.Open()
+		if err != nil {
+			fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+		defer s.Close()
+
+		c, err := s.Load()
+		if err != nil {
+			fmt.Printf("%sError loading config: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		todo := resolveTodo(c, ref)
+		if todo == nil {
+			return
+		}
+
+		displayTodoDetails(c, *todo)
+	},
+}
+
+func displayTodoDetails(c *types.Config, todo types.Todo) {
+	now := time.Now()
+	field := func(name, value string) {
+		fmt.Printf("%s%-10s%s %s\n", config.Cyan, name+":", config.Reset, value)
+	}
+	stamp := func(t time.Time) string {
+		return fmt.Sprintf("%s (%s)", utils.FormatDateTime(t, userSettings.DateFormat), utils.FormatAge(t, now))
+	}
+
+	fmt.Printf("\n%s[%s%d%s] %s%s\n", config.Bold, config.Purple, todo.Number, config.Reset+config.Bold, todo.Task, config.Reset)
+	fmt.Println(strings.Repeat("=", 40))
+
+	field("ID", todo.ID)
+
+	w := utils.StoreWorkflow(c)
+	state, ok := utils.FindState(w, todo.Status)
+	status := config.Red + todo.Status + " (not in the workflow)" + config.Reset
+	if ok {
+		status = statusColor(w, state) + state.Name + config.Reset
+	}
+	field("Status", status)
+
+	urgencyText, urgencyColor := utils.GetUrgencyDisplay(todo.Urgency)
+	field("Urgency", urgencyColor+urgencyText+config.Reset)
+	field("Group", displayGroupName(todo.Group))
+
+	if len(todo.Tags) > 0 {
+		field("Tags", config.Blue+formatTags(todo.Tags)+config.Reset)
+	}
+	if len(todo.Fields) > 0 {
+		field("Fields", strings.Join(formatFields(c.Fields, todo.Fields), ", "))
+	}
+	if todo.Due != nil {
+		field("Due", utils.FormatDue(*todo.Due, userSettings.DateFormat, now))
+	}
+	if isWaiting(todo.Wait, now) {
+		field("Snoozed", "until "+utils.FormatDue(*todo.Wait, userSettings.DateFormat, now))
+	}
+	if todo.Recur != "" {
+		field("Repeats", todo.Recur)
+	}
+	if todo.Parent != "" {
+		parent := "#" + todoRef(c, todo.Parent)
+		if p := findTodo(c, todo.Parent); p != nil {
+			parent += " " + p.Task
+		}
+		field("Parent", parent)
+	}
+	if counts, ok := subtaskProgress(c.Todos)[todo.ID]; ok {
+		field("Subtasks", fmt.Sprintf("%d/%d done", counts[0], counts[1]))
+		for _, child := range c.Todos {
+			if child.Parent == todo.ID {
+				mark := statusMark(w, child.Status)
+				fmt.Printf("           %s [%s%d%s] %s\n", mark, config.Purple, child.Number, config.Reset, child.Task)
+			}
+		}
+	}
+	if len(todo.DependsOn) > 0 {
+		deps := formatTodoRefs(c, todo.DependsOn)
+		if open := blockers(c, todo); len(open) > 0 && !todo.Completed {
+			deps += config.Red + " (blocked by " + formatTodoRefs(c, open) + ")" + config.Reset
+		}
+		field("Depends", deps)
+	}
+
+	if len(todo.Links) > 0 {
+		field("Links", strconv.Itoa(len(todo.Links)))
+		printLinks(todo.Links, "           ")
+	}
+
+	if len(todo.TimeLog) > 0 {
+		tracked := utils.FormatDuration(totalTime(todo, now))
+		if timerRunning(todo) {
+			tracked += config.Green + " (timer running)" + config.Reset
+		}
+		field("Tracked", tracked)
+	}
+
+	field("Created", stamp(todo.CreatedAt))
+	field("Updated", stamp(todo.UpdatedAt))
+	if todo.CompletedAt != nil {
+		field("Completed", stamp(*todo.CompletedAt))
+	}
+
+	if todo.Notes != "" {
+		fmt.Printf("\n%sNotes:%s\n", config.Cyan, config.Reset)
+		fmt.Println(todo.Notes)
+	}
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dorukozerr/todo-cli/internal/config"
+	"github.com/dorukozerr/todo-cli/internal/fs"
+	"github.com/dorukozerr/todo-cli/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var snoozeCmd = &cobra.Command{
+	Use:   "snooze [todo-id] [duration|date]",
+	Short: "Hide a todo from the default list until a later date",
+	Long: `Hide a todo from the default 'todo list' until a later date, given as a
+duration from now (3d, 2w, 4h) or a date (mon, 'next month', 2026-11-02).
+Whole days wake the todo at the start of that day. 'none' wakes it now.
+
+See snoozed todos with 'todo list --waiting'.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		id := args[0]
+
+		now := time.Now()
+		wait, err := parseWait(args[1], now)
+		if err != nil {
+			fmt.Printf("%s%v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		s, err := fs.Open()
+		if err != nil {
+			fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+		defer s.Close()
+
+		todo, err := s.ResolveTodo(id)
+		if err != nil {
+			reportTodoError(id, err)
+			return
+		}
+
+		if wait == nil && !isWaiting(todo.Wait, now) {
+			fmt.Printf("%sTodo [%d] is not snoozed%s\n", config.Yellow, todo.Number, config.Reset)
+			return
+		}
+
+		todo.Wait = wait
+		todo.UpdatedAt = now
+		if err = s.PutTodo(*todo); err != nil {
+			fmt.Printf("%sError saving config: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		if wait == nil {
+			fmt.Printf("%sTodo [%s%d%s] is back in the list: %s%s%s\n", config.Green,
+				config.Purple, todo.Number, config.Green,
+				config.Bold, todo.Task, config.Reset)
+			return
+		}
+		fmt.Printf("%sSnoozed todo [%s%d%s] until %s: %s%s%s\n", config.Green,
+			config.Purple, todo.Number, config.Green,
+			utils.FormatDue(*wait, userSettings.DateFormat, now),
+			config.Bold, todo.Task, config.Reset)
+		if todo.Due != nil && todo.Due.Before(*wait) {
+			fmt.Printf("  %sNote:%s it is due %s, before it comes back\n",
+				config.Yellow, config.Reset, utils.FormatDue(*todo.Due, userSettings.DateFormat, now))
+		}
+	},
+}
+
+// parseWait reads a snooze or --wait value: a duration from now, a date, or
+// "none" for no wait. Durations given in days or weeks ("3d", "2w") end at
+// the start of a day; "48h" is exactly 48 hours from now.
+func parseWait(value string, now time.Time) (*time.Time, error) {
+	if value == "none" {
+		return nil, nil
+	}
+
+	var wait time.Time
+	if d, err := utils.ParseDuration(value); err == nil {
+		wait = now.Add(d)
+		if strings.HasSuffix(value, "d") || strings.HasSuffix(value, "w") {
+			wait = utils.StartOfDay(now).AddDate(0, 0, int(d/(24*time.Hour)))
+		}
+	} else {
+		wait, err = utils.ParseDue(value, now)
+		if err != nil {
+			return nil, fmt.Errorf("invalid wait '%s', use e.g. 3d, mon or 2026-11-02", value)
+		}
+	}
+
+	if !wait.After(now) {
+		return nil, fmt.Errorf("wait '%s' is not in the future", value)
+	}
+	return &wait, nil
+}
+
+// isWaiting reports whether a wait date still hides its todo.
+func isWaiting(wait *time.Time, now time.Time) bool {
+	return wait != nil && wait.After(now)
+}
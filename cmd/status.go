@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dorukozerr/todo-cli/internal/config"
+	"github.com/dorukozerr/todo-cli/internal/fs"
+	"github.com/dorukozerr/todo-cli/internal/types"
+	"github.com/dorukozerr/todo-cli/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status [todo-id] [state]",
+	Short: "Move a todo to another state of the workflow",
+	Long: `Move a todo to another state of the store's workflow, e.g.
+'todo status 3 in_progress'. See the states and the allowed moves with
+'todo workflow'.
+
+Moving a todo to a closed state works like 'todo complete': open subtasks
+must be closed first or closed along with it using --recursive, a running
+timer is stopped and a recurring todo gets its next instance. Subtasks
+closed with --recursive go through the same checks and the same steps.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		recursive, _ := cmd.Flags().GetBool("recursive")
+		name := utils.NormalizeStatus(args[1])
+		changeStatus(args[0], func(w types.Workflow, todo types.Todo) string { return name }, recursive)
+	},
+}
+
+// changeStatus moves a todo to the state target picks once the workflow and
+// the todo are known, which lets 'complete' and 'incomplete' follow custom
+// workflows.
+func changeStatus(ref string, target func(w types.Workflow, todo types.Todo) string, recursive bool) {
+	s, err := fs.Open()
+	if err != nil {
+		fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
+		return
+	}
+	defer s.Close()
+
+	c, err := s.Load()
+	if err != nil {
+		fmt.Printf("%sError loading config: %v%s\n", config.Red, err, config.Reset)
+		return
+	}
+
+	todo := resolveTodo(c, ref)
+	if todo == nil {
+		return
+	}
+	id, number := todo.ID, todo.Number
+
+	w := utils.StoreWorkflow(c)
+	name := target(w, *todo)
+	state, ok := utils.FindState(w, name)
+	if !ok {
+		fmt.Printf("%sUnknown state '%s', the workflow has: %s%s\n", config.Red, name, stateNames(w), config.Reset)
+		return
+	}
+	if todo.Status == state.Name {
+		fmt.Printf("%sTodo [%d] is already %s%s\n", config.Yellow, number, state.Name, config.Reset)
+		return
+	}
+	if !utils.CanTransition(w, todo.Status, state.Name) {
+		fmt.Printf("%sCannot move todo [%d] from %s to %s, it can move to: %s%s\n",
+			config.Red, number, todo.Status, state.Name, allowedMoves(w, todo.Status), config.Reset)
+		return
+	}
+
+	closing := state.Closed && !todo.Completed
+	var open []string
+	if closing {
+		open = openSubtasks(c.Todos, id)
+		if len(open) > 0 && !recursive {
+			fmt.Printf("%sTodo [%d] still has %d open subtask(s), close them first or use --recursive%s\n",
+				config.Yellow, number, len(open), config.Reset)
+			return
+		}
+	}
+
+	// Subtasks closed along with the todo go through the same checks and
+	// the same close path as the todo itself.
+	for _, subtaskID := range open {
+		subtask := findTodo(c, subtaskID)
+		if !utils.CanTransition(w, subtask.Status, state.Name) {
+			fmt.Printf("%sCannot move subtask [%d] from %s to %s, it can move to: %s%s\n",
+				config.Red, subtask.Number, subtask.Status, state.Name, allowedMoves(w, subtask.Status), config.Reset)
+			return
+		}
+	}
+
+	now := time.Now()
+	blockedBefore := blockedTodos(c)
+
+	var nexts []types.Todo
+	for _, closeID := range append(slices.Clone(open), id) {
+		t := findTodo(c, closeID)
+		applyStatus(t, state, now)
+		if !closing {
+			continue
+		}
+		if timerRunning(*t) {
+			stopTimer(t, now)
+		}
+		if t.Recur != "" {
+			next, err := nextRecurrence(*c, *t, now)
+			if err != nil {
+				fmt.Printf("%s%v%s\n", config.Red, err, config.Reset)
+				return
+			}
+			t.Recur = ""
+			c.Todos = append(c.Todos, next)
+			nexts = append(nexts, next)
+		}
+	}
+	todo = findTodo(c, id)
+
+	if len(open) == 0 && len(nexts) == 0 {
+		err = s.PutTodo(*todo)
+	} else {
+		err = s.Save(c)
+	}
+	if err != nil {
+		fmt.Printf("%sError saving config: %v%s\n", config.Red, err, config.Reset)
+		return
+	}
+
+	if state.Name == w.Done {
+		fmt.Printf("%sCompleted todo [%s%d%s]: %s%s%s\n", config.Green,
+			config.Purple, number, config.Green,
+			config.Bold, todo.Task, config.Reset)
+	} else {
+		color := statusColor(w, state)
+		fmt.Printf("%sMarked todo [%s%d%s] as %s: %s%s%s\n", color,
+			config.Purple, number, color, state.Name,
+			config.Bold, todo.Task, config.Reset)
+	}
+	if len(open) > 0 {
+		fmt.Printf("  %sAlso marked %d subtask(s) as %s%s\n", config.Cyan, len(open), state.Name, config.Reset)
+	}
+	for _, next := range nexts {
+		fmt.Printf("  %sNext:%s [%s%d%s] due %s (%s)\n",
+			config.Cyan, config.Reset,
+			config.Purple, next.Number, config.Reset,
+			utils.FormatDue(*next.Due, userSettings.DateFormat, now), next.Recur)
+	}
+
+	blockedAfter := blockedTodos(c)
+	for _, other := range c.Todos {
+		if blockedBefore[other.ID] && !blockedAfter[other.ID] {
+			fmt.Printf("  %sUnblocked:%s [%s%d%s] %s\n",
+				config.Cyan, config.Reset,
+				config.Purple, other.Number, config.Reset, other.Task)
+		}
+	}
+
+	applyArchivePolicy(s)
+}
+
+// applyStatus moves todo to state, keeping Completed and CompletedAt in
+// line with whether the state is closed.
+func applyStatus(todo *types.Todo, state types.State, now time.Time) {
+	if state.Closed && !todo.Completed {
+		todo.CompletedAt = &now
+	} else if !state.Closed {
+		todo.CompletedAt = nil
+	}
+	todo.Status = state.Name
+	todo.Completed = state.Closed
+	todo.UpdatedAt = now
+}
+
+// statusColor is green for closed states, yellow for the initial state and
+// cyan for the states work is happening in.
+func statusColor(w types.Workflow, state types.State) string {
+	switch {
+	case state.Closed:
+		return config.Green
+	case state.Name == w.Initial:
+		return config.Yellow
+	}
+	return config.Cyan
+}
+
+// statusMark is the "[x]" style box shown in front of a todo.
+func statusMark(w types.Workflow, status string) string {
+	state, ok := utils.FindState(w, status)
+	if !ok {
+		return "[?]"
+	}
+	return "[" + utils.StateSymbol(state) + "]"
+}
+
+func allowedMoves(w types.Workflow, status string) string {
+	allowed := strings.Join(w.Transitions[status], ", ")
+	if allowed == "" {
+		return "no other state"
+	}
+	return allowed
+}
+
+func stateNames(w types.Workflow) string {
+	names := make([]string, len(w.States))
+	for i, state := range w.States {
+		names[i] = state.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+func init() {
+	statusCmd.Flags().BoolP("recursive", "r", false, "Also close open subtasks when moving to a closed state")
+}
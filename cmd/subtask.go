@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dorukozerr/todo-cli/internal/config"
+	"github.com/dorukozerr/todo-cli/internal/fs"
+	"github.com/dorukozerr/todo-cli/internal/types"
+)
+
+const (
+	childrenDelete = "delete"
+	childrenKeep   = "keep"
+)
+
+func findTodo(c *types.Config, id string) *types.Todo {
+	for i := range c.Todos {
+		if c.Todos[i].ID == id {
+			return &c.Todos[i]
+		}
+	}
+	return nil
+}
+
+// resolveTodo finds the todo a user means by ref, its number or a unique
+// prefix of its UUID, and reports it when there is none.
+func resolveTodo(c *types.Config, ref string) *types.Todo {
+	todo, err := fs.ResolveTodo(c, ref)
+	if err != nil {
+		reportTodoError(ref, err)
+		return nil
+	}
+	return todo
+}
+
+func reportTodoError(ref string, err error) {
+	switch {
+	case errors.Is(err, fs.ErrTodoNotFound):
+		fmt.Printf("%sTodo with ID '%s' not found%s\n", config.Red, ref, config.Reset)
+	case errors.Is(err, fs.ErrAmbiguousID):
+		fmt.Printf("%s%v%s\n", config.Red, err, config.Reset)
+	default:
+		fmt.Printf("%sError loading config: %v%s\n", config.Red, err, config.Reset)
+	}
+}
+
+// subtasks returns the IDs of every descendant of id, children before
+// grandchildren. A parent cycle left by a hand edit is not followed twice.
+func subtasks(todos []types.Todo, id string) []string {
+	seen := map[string]bool{id: true}
+	var ids []string
+
+	queue := []string{id}
+	for len(queue) > 0 {
+		parent := queue[0]
+		queue = queue[1:]
+		for _, todo := range todos {
+			if todo.Parent == parent && !seen[todo.ID] {
+				seen[todo.ID] = true
+				ids = append(ids, todo.ID)
+				queue = append(queue, todo.ID)
+			}
+		}
+	}
+
+	return ids
+}
+
+func openSubtasks(todos []types.Todo, id string) []string {
+	var open []string
+	for _, subtaskID := range subtasks(todos, id) {
+		for _, todo := range todos {
+			if todo.ID == subtaskID && !todo.Completed {
+				open = append(open, subtaskID)
+			}
+		}
+	}
+	return open
+}
+
+// subtaskProgress counts the completed and total direct children of every
+// parent.
+func subtaskProgress(todos []types.Todo) map[string][2]int {
+	progress := make(map[string][2]int)
+	for _, todo := range todos {
+		if todo.Parent == "" {
+			continue
+		}
+		counts := progress[todo.Parent]
+		if todo.Completed {
+			counts[0]++
+		}
+		counts[1]++
+		progress[todo.Parent] = counts
+	}
+	return progress
+}
+
+type treeRow struct {
+	todo  types.Todo
+	depth int
+}
+
+// todoTree orders todos so that children follow their parent, keeping the
+// existing order among siblings. Todos whose parent is not in the list are
+// shown at the top level.
+func todoTree(todos []types.Todo) []treeRow {
+	present := make(map[string]bool)
+	for _, todo := range todos {
+		present[todo.ID] = true
+	}
+
+	children := make(map[string][]types.Todo)
+	var roots []types.Todo
+	for _, todo := range todos {
+		if todo.Parent != "" && todo.Parent != todo.ID && present[todo.Parent] {
+			children[todo.Parent] = append(children[todo.Parent], todo)
+		} else {
+			roots = append(roots, todo)
+		}
+	}
+
+	var rows []treeRow
+	placed := make(map[string]bool)
+	var walk func(todo types.Todo, depth int)
+	walk = func(todo types.Todo, depth int) {
+		if placed[todo.ID] {
+			return
+		}
+		placed[todo.ID] = true
+		rows = append(rows, treeRow{todo: todo, depth: depth})
+		for _, child := range children[todo.ID] {
+			walk(child, depth+1)
+		}
+	}
+
+	for _, root := range roots {
+		walk(root, 0)
+	}
+	for _, todo := range todos {
+		walk(todo, 0)
+	}
+
+	return rows
+}
+
+// askChildren asks what to do with the subtasks of a todo being deleted.
+func askChildren(count int) string {
+	fmt.Printf("%sThis todo has %d subtask(s).%s [d]elete them too, [k]eep them (moved up a level), [c]ancel? ",
+		config.Yellow, count, config.Reset)
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		fmt.Println()
+		return ""
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "d", "delete":
+		return childrenDelete
+	case "k", "keep":
+		return childrenKeep
+	}
+	return ""
+}
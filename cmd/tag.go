@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dorukozerr/todo-cli/internal/config"
+	"github.com/dorukozerr/todo-cli/internal/fs"
+	"github.com/dorukozerr/todo-cli/internal/types"
+	"github.com/spf13/cobra"
+)
+
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Add or remove tags on a todo",
+	Long: `Add or remove tags on a todo:
+- tag add <id> <tag>...: Add tags
+- tag remove <id> <tag>...: Remove tags
+
+Tags cut across groups. Filter on them with 'todo list --tag x --tag -y'
+and see them all with 'todo tags'.`,
+}
+
+var tagAddCmd = &cobra.Command{
+	Use:   "add [todo-id] [tag...]",
+	Short: "Add tags to a todo",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		updateTags(args[0], args[1:], nil)
+	},
+}
+
+var tagRemoveCmd = &cobra.Command{
+	Use:   "remove [todo-id] [tag...]",
+	Short: "Remove tags from a todo",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		updateTags(args[0], nil, args[1:])
+	},
+}
+
+var tagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "List tags with the number of todos using them",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		s, err := fs.Open()
+		if err != nil {
+			fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+		defer s.Close()
+
+		c, err := s.Load()
+		if err != nil {
+			fmt.Printf("%sError loading config: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		total := make(map[string]int)
+		open := make(map[string]int)
+		for _, todo := range c.Todos {
+			for _, tag := range todo.Tags {
+				total[tag]++
+				if !todo.Completed {
+					open[tag]++
+				}
+			}
+		}
+
+		if len(total) == 0 {
+			fmt.Printf("%sNo tags found%s\n", config.Yellow, config.Reset)
+			return
+		}
+
+		var tags []string
+		for tag := range total {
+			tags = append(tags, tag)
+		}
+		sort.Slice(tags, func(i, j int) bool {
+			if open[tags[i]] != open[tags[j]] {
+				return open[tags[i]] > open[tags[j]]
+			}
+			return tags[i] < tags[j]
+		})
+
+		fmt.Printf("%sTags (%d total):%s\n", config.Blue+config.Bold, len(tags), config.Reset)
+		for _, tag := range tags {
+			fmt.Printf("  %s+%-20s%s %d open, %d total\n", config.Blue, tag, config.Reset, open[tag], total[tag])
+		}
+	},
+}
+
+func updateTags(ref string, add, remove []string) {
+	add, err := normalizeTags(add)
+	if err == nil {
+		remove, err = normalizeTags(remove)
+	}
+	if err != nil {
+		fmt.Printf("%s%v%s\n", config.Red, err, config.Reset)
+		return
+	}
+
+	s, err := fs.Open()
+	if err != nil {
+		fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
+		return
+	}
+	defer s.Close()
+
+	todo, err := s.ResolveTodo(ref)
+	if err != nil {
+		reportTodoError(ref, err)
+		return
+	}
+
+	todo.Tags = applyTags(todo.Tags, add, remove)
+	todo.UpdatedAt = time.Now()
+
+	if err = s.PutTodo(*todo); err != nil {
+		fmt.Printf("%sError saving config: %v%s\n", config.Red, err, config.Reset)
+		return
+	}
+
+	fmt.Printf("%sUpdated tags of todo [%s%d%s]: %s%s\n", config.Green,
+		config.Purple, todo.Number, config.Green,
+		formatTags(todo.Tags), config.Reset)
+}
+
+// normalizeTags lowercases tags and drops a leading '+'. A leading '-' is
+// reserved for excluding a tag in filters.
+func normalizeTags(tags []string) ([]string, error) {
+	var normalized []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "+"))
+		if tag == "" || strings.HasPrefix(tag, "-") || strings.ContainsAny(tag, " \t,") {
+			return nil, fmt.Errorf("invalid tag '%s', tags are single words that do not start with '-'", tag)
+		}
+		normalized = append(normalized, tag)
+	}
+	return normalized, nil
+}
+
+// splitTagArgs separates --tag values into tags to add and, when they start
+// with '-', tags to remove or exclude.
+func splitTagArgs(args []string) ([]string, []string, error) {
+	var include, exclude []string
+	for _, arg := range args {
+		if tag, ok := strings.CutPrefix(arg, "-"); ok {
+			exclude = append(exclude, tag)
+		} else {
+			include = append(include, arg)
+		}
+	}
+
+	include, err := normalizeTags(include)
+	if err != nil {
+		return nil, nil, err
+	}
+	exclude, err = normalizeTags(exclude)
+	if err != nil {
+		return nil, nil, err
+	}
+	return include, exclude, nil
+}
+
+func applyTags(tags, add, remove []string) []string {
+	for _, tag := range add {
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	tags = slices.DeleteFunc(tags, func(tag string) bool {
+		return slices.Contains(remove, tag)
+	})
+	if len(tags) == 0 {
+		return nil
+	}
+	return tags
+}
+
+// matchTags reports whether todo has every included tag and none of the
+// excluded ones.
+func matchTags(todo types.Todo, include, exclude []string) bool {
+	for _, tag := range include {
+		if !slices.Contains(todo.Tags, tag) {
+			return false
+		}
+	}
+	for _, tag := range exclude {
+		if slices.Contains(todo.Tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+func formatTags(tags []string) string {
+	if len(tags) == 0 {
+		return "no tags"
+	}
+	return "+" + strings.Join(tags, " +")
+}
+
+func init() {
+	tagCmd.AddCommand(tagAddCmd)
+	tagCmd.AddCommand(tagRemoveCmd)
+}
@@ -0,0 +1,409 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dorukozerr/todo-cli/internal/config"
+	"github.com/dorukozerr/todo-cli/internal/fs"
+	"github.com/dorukozerr/todo-cli/internal/types"
+	"github.com/dorukozerr/todo-cli/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var startCmd = &cobra.Command{
+	Use:   "start [todo-id]",
+	Short: "Start tracking time on a todo",
+	Long: `Start tracking time on a todo. Only one timer runs at a time, so a
+timer running on another todo is stopped first.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ref := args[0]
+
+		s, err := fs.Open()
+		if err != nil {
+			fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+		defer s.Close()
+
+		c, err := s.Load()
+		if err != nil {
+			fmt.Printf("%sError loading config: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		todo := resolveTodo(c, ref)
+		if todo == nil {
+			return
+		}
+		if todo.Completed {
+			fmt.Printf("%sTodo [%d] is completed%s\n", config.Red, todo.Number, config.Reset)
+			return
+		}
+		if timerRunning(*todo) {
+			fmt.Printf("%sTimer already running on todo [%d]%s\n", config.Yellow, todo.Number, config.Reset)
+			return
+		}
+
+		now := time.Now()
+		stopped := runningTodo(c)
+		if stopped != nil {
+			stopTimer(stopped, now)
+		}
+
+		todo.TimeLog = append(todo.TimeLog, types.TimeEntry{Start: now})
+		todo.UpdatedAt = now
+
+		if stopped == nil {
+			err = s.PutTodo(*todo)
+		} else {
+			err = s.Save(c)
+		}
+		if err != nil {
+			fmt.Printf("%sError saving config: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		if stopped != nil {
+			fmt.Printf("%sStopped timer on [%s%d%s] %s (%s total)%s\n", config.Yellow,
+				config.Purple, stopped.Number, config.Yellow,
+				stopped.Task, utils.FormatDuration(totalTime(*stopped, now)), config.Reset)
+		}
+		fmt.Printf("%sStarted timer on [%s%d%s]: %s%s%s\n", config.Green,
+			config.Purple, todo.Number, config.Green,
+			config.Bold, todo.Task, config.Reset)
+	},
+}
+
+var stopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the running timer",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		s, err := fs.Open()
+		if err != nil {
+			fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+		defer s.Close()
+
+		c, err := s.Load()
+		if err != nil {
+			fmt.Printf("%sError loading config: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		todo := runningTodo(c)
+		if todo == nil {
+			fmt.Printf("%sNo timer is running%s\n", config.Yellow, config.Reset)
+			return
+		}
+
+		now := time.Now()
+		session := stopTimer(todo, now)
+		if err = s.PutTodo(*todo); err != nil {
+			fmt.Printf("%sError saving config: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		fmt.Printf("%sStopped timer on [%s%d%s]: %s%s%s\n", config.Green,
+			config.Purple, todo.Number, config.Green,
+			config.Bold, todo.Task, config.Reset)
+		fmt.Printf("  %sThis session:%s %s | %sTotal:%s %s\n",
+			config.Cyan, config.Reset, utils.FormatDuration(session),
+			config.Cyan, config.Reset, utils.FormatDuration(totalTime(*todo, now)))
+	},
+}
+
+var logCmd = &cobra.Command{
+	Use:   "log [todo-id] [duration]",
+	Short: "Record time worked on a todo after the fact",
+	Long: `Record time worked on a todo after the fact, e.g. 'todo log 12 1h30m'.
+The time is recorded as ending now, or at --at, which takes the same
+phrases as --due: 'yesterday 5pm', '2026-10-14 18:00'.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		ref := args[0]
+		at, _ := cmd.Flags().GetString("at")
+
+		duration, err := utils.ParseDuration(args[1])
+		if err != nil || duration <= 0 {
+			fmt.Printf("%sInvalid duration '%s', use e.g. 45m or 1h30m%s\n", config.Red, args[1], config.Reset)
+			return
+		}
+
+		now := time.Now()
+		end := now
+		if at != "" {
+			end, err = utils.ParseDue(at, now)
+			if err != nil {
+				fmt.Printf("%s%v%s\n", config.Red, err, config.Reset)
+				return
+			}
+			if end.After(now) {
+				fmt.Printf("%s--at cannot be in the future%s\n", config.Red, config.Reset)
+				return
+			}
+		}
+		start := end.Add(-duration)
+
+		s, err := fs.Open()
+		if err != nil {
+			fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+		defer s.Close()
+
+		c, err := s.Load()
+		if err != nil {
+			fmt.Printf("%sError loading config: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		todo := resolveTodo(c, ref)
+		if todo == nil {
+			return
+		}
+
+		todo.TimeLog = append(todo.TimeLog, types.TimeEntry{Start: start, End: &end})
+		sort.SliceStable(todo.TimeLog, func(i, j int) bool {
+			return todo.TimeLog[i].Start.Before(todo.TimeLog[j].Start)
+		})
+		todo.UpdatedAt = now
+
+		if err = s.PutTodo(*todo); err != nil {
+			fmt.Printf("%sError saving config: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		fmt.Printf("%sLogged %s on [%s%d%s] %s, %s total%s\n", config.Green,
+			utils.FormatDuration(duration),
+			config.Purple, todo.Number, config.Green,
+			todo.Task, utils.FormatDuration(totalTime(*todo, now)), config.Reset)
+	},
+}
+
+var timeCmd = &cobra.Command{
+	Use:   "time",
+	Short: "Report tracked time",
+	Long: `Report tracked time:
+- time report: Total time per todo, per group and per day`,
+}
+
+var timeReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Show tracked time per todo, group and day",
+	Long: `Show the time tracked since --since (default 7d) per todo, per group
+and per day, across all groups and including archived todos. --since takes
+a duration such as 30d or a date phrase such as 'mon' or 2026-10-01.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		sinceFlag, _ := cmd.Flags().GetString("since")
+		group, _ := cmd.Flags().GetString("group")
+
+		now := time.Now()
+		since, err := parseSince(sinceFlag, now)
+		if err != nil {
+			fmt.Printf("%s%v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		s, err := fs.Open()
+		if err != nil {
+			fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+		defer s.Close()
+
+		c, err := s.Load()
+		if err != nil {
+			fmt.Printf("%sError loading config: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		archive, err := s.LoadArchive()
+		if err != nil {
+			fmt.Printf("%sError loading archive: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		todos := append([]types.Todo{}, c.Todos...)
+		for _, entry := range archive.Todos {
+			todos = append(todos, entry.Todo)
+		}
+
+		byTodo := make(map[string]time.Duration)
+		byGroup := make(map[string]time.Duration)
+		byDay := make(map[string]time.Duration)
+		var total time.Duration
+		var tracked []types.Todo
+
+		for _, todo := range todos {
+			if group != "" && todo.Group != groupKey(group) {
+				continue
+			}
+			for _, entry := range todo.TimeLog {
+				end := now
+				if entry.End != nil {
+					end = *entry.End
+				}
+				for day, d := range splitByDay(entry.Start, end, since, now) {
+					if byTodo[todo.ID] == 0 {
+						tracked = append(tracked, todo)
+					}
+					byTodo[todo.ID] += d
+					byGroup[todo.Group] += d
+					byDay[day] += d
+					total += d
+				}
+			}
+		}
+
+		fmt.Printf("\n%sTime tracked since %s:%s\n", config.Blue+config.Bold,
+			utils.FormatDate(since, userSettings.DateFormat), config.Reset)
+		fmt.Println(strings.Repeat("=", 40))
+
+		if total == 0 {
+			fmt.Printf("%sNo time tracked in this range%s\n", config.Yellow, config.Reset)
+			return
+		}
+
+		sort.Slice(tracked, func(i, j int) bool {
+			return byTodo[tracked[i].ID] > byTodo[tracked[j].ID]
+		})
+		fmt.Printf("\n%sBy todo%s\n", config.Cyan+config.Bold, config.Reset)
+		for _, todo := range tracked {
+			running := ""
+			if timerRunning(todo) {
+				running = config.Green + " (running)" + config.Reset
+			}
+			fmt.Printf("  %8s  [%s%d%s] %s %s(%s)%s%s\n",
+				utils.FormatDuration(byTodo[todo.ID]),
+				config.Purple, todo.Number, config.Reset,
+				todo.Task, config.Yellow, displayGroupName(todo.Group), config.Reset, running)
+		}
+
+		fmt.Printf("\n%sBy group%s\n", config.Cyan+config.Bold, config.Reset)
+		for _, name := range sortedKeys(byGroup) {
+			fmt.Printf("  %8s  %s\n", utils.FormatDuration(byGroup[name]), displayGroupName(name))
+		}
+
+		fmt.Printf("\n%sBy day%s\n", config.Cyan+config.Bold, config.Reset)
+		for _, day := range sortedKeys(byDay) {
+			t, _ := time.ParseInLocation("2006-01-02", day, time.Local)
+			fmt.Printf("  %8s  %s %s\n", utils.FormatDuration(byDay[day]), t.Format("Mon"), utils.FormatDate(t, userSettings.DateFormat))
+		}
+
+		fmt.Printf("\n%sTotal:%s %s\n", config.Bold, config.Reset, utils.FormatDuration(total))
+	},
+}
+
+func timerRunning(todo types.Todo) bool {
+	for _, entry := range todo.TimeLog {
+		if entry.End == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func runningTodo(c *types.Config) *types.Todo {
+	for i := range c.Todos {
+		if timerRunning(c.Todos[i]) {
+			return &c.Todos[i]
+		}
+	}
+	return nil
+}
+
+// stopTimer ends the running interval of todo and returns its length.
+func stopTimer(todo *types.Todo, now time.Time) time.Duration {
+	var session time.Duration
+	for i := range todo.TimeLog {
+		if entry := &todo.TimeLog[i]; entry.End == nil {
+			entry.End = &now
+			session += now.Sub(entry.Start)
+		}
+	}
+	todo.UpdatedAt = now
+	return session
+}
+
+func totalTime(todo types.Todo, now time.Time) time.Duration {
+	var total time.Duration
+	for _, entry := range todo.TimeLog {
+		end := now
+		if entry.End != nil {
+			end = *entry.End
+		}
+		total += end.Sub(entry.Start)
+	}
+	return total
+}
+
+// splitByDay clips the interval start-end to since-now and splits it at
+// local midnights, keyed by ISO date.
+func splitByDay(start, end, since, now time.Time) map[string]time.Duration {
+	if start.Before(since) {
+		start = since
+	}
+	if end.After(now) {
+		end = now
+	}
+
+	days := make(map[string]time.Duration)
+	for start.Before(end) {
+		next := utils.StartOfDay(start).AddDate(0, 0, 1)
+		if next.After(end) {
+			next = end
+		}
+		days[start.In(time.Local).Format("2006-01-02")] += next.Sub(start)
+		start = next
+	}
+	return days
+}
+
+// parseSince reads --since as a duration back from now ("7d") or as a date
+// phrase ("mon", "2026-10-01"). Durations in days or weeks cover whole days,
+// today included; "48h" starts exactly 48 hours ago. A weekday name means
+// the last one, and any other date in the future is refused.
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if d, err := utils.ParseDuration(value); err == nil {
+		if strings.HasSuffix(value, "d") || strings.HasSuffix(value, "w") {
+			return utils.StartOfDay(now).Add(-d + 24*time.Hour), nil
+		}
+		return now.Add(-d), nil
+	}
+
+	t, err := utils.ParseDue(value, now)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since '%s', use e.g. 7d or mon", value)
+	}
+	if t.After(now) {
+		if words := strings.Fields(value); !utils.IsWeekday(words[0]) {
+			return time.Time{}, fmt.Errorf("--since '%s' is in the future", value)
+		}
+		t = t.AddDate(0, 0, -7)
+	}
+	return t, nil
+}
+
+func sortedKeys(m map[string]time.Duration) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func init() {
+	logCmd.Flags().String("at", "", "When the work ended, e.g. 'yesterday 5pm' (default now)")
+
+	timeReportCmd.Flags().String("since", "7d", "Start of the report, e.g. 30d, mon or 2026-10-01")
+	timeReportCmd.Flags().StringP("group", "g", "", "Only report on this group")
+	timeCmd.AddCommand(timeReportCmd)
+}
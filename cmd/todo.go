@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/dorukozerr/todo-cli/internal/config"
 	"github.com/dorukozerr/todo-cli/internal/fs"
@@ -22,6 +26,9 @@ var addCmd = &cobra.Command{
 		if err != nil {
 			return
 		}
+		if !cmd.Flags().Changed("urgency") {
+			urgency = userSettings.DefaultUrgency
+		}
 
 		if urgency < 1 || urgency > 5 {
 			fmt.Printf("%sUrgency must be between 1 and 5%s\n", config.Red, config.Reset)
@@ -33,15 +40,85 @@ var addCmd = &cobra.Command{
 			return
 		}
 
-		c, err := fs.GetConfig()
+		due, err := dueFlag(cmd)
+		if err != nil {
+			fmt.Printf("%s%v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		parent, _ := cmd.Flags().GetString("parent")
+		every, _ := cmd.Flags().GetString("every")
+
+		var wait *time.Time
+		if value, _ := cmd.Flags().GetString("wait"); value != "" {
+			wait, err = parseWait(value, time.Now())
+			if err != nil {
+				fmt.Printf("%s%v%s\n", config.Red, err, config.Reset)
+				return
+			}
+		}
+
+		tagArgs, _ := cmd.Flags().GetStringArray("tag")
+		tags, err := normalizeTags(tagArgs)
+		if err != nil {
+			fmt.Printf("%s%v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		setArgs, _ := cmd.Flags().GetStringArray("set")
+		recur := ""
+		if every != "" {
+			rule, err := utils.ParseRecurrence(every)
+			if err != nil {
+				fmt.Printf("%s%v%s\n", config.Red, err, config.Reset)
+				return
+			}
+			if due == nil {
+				first := rule.First(time.Now())
+				due = &first
+			}
+			recur = rule.Anchored(*due).String()
+		}
+
+		s, err := fs.Open()
+		if err != nil {
+			fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+		defer s.Close()
+
+		c, err := s.Load()
 		if err != nil {
 			fmt.Printf("%sError loading config: %v%s\n", config.Red, err, config.Reset)
 			return
 		}
 
+		setFields, _, err := parseSetArgs(c.Fields, setArgs, time.Now())
+		if err != nil {
+			fmt.Printf("%s%v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		if parent != "" {
+			parentTodo, err := fs.ResolveTodo(c, parent)
+			if errors.Is(err, fs.ErrTodoNotFound) {
+				fmt.Printf("%sParent todo '%s' not found%s\n", config.Red, parent, config.Reset)
+				return
+			}
+			if err != nil {
+				fmt.Printf("%s%v%s\n", config.Red, err, config.Reset)
+				return
+			}
+			parent = parentTodo.ID
+			if group == "" {
+				group = displayGroupName(parentTodo.Group)
+			}
+		}
+
 		if group == "" {
 			group = c.ActiveGroup
 		}
+		group = groupKey(group)
 
 		if group != "" {
 			groupExists := false
@@ -57,17 +134,26 @@ var addCmd = &cobra.Command{
 			}
 		}
 
-		id := utils.GenerateNextTodoID(*c)
+		number := utils.NextTodoNumber(*c)
+		now := time.Now()
 		newTodo := types.Todo{
-			ID:        id,
+			ID:        utils.GenerateUUID(),
+			Number:    number,
 			Task:      task,
 			Urgency:   urgency,
 			Group:     group,
-			Completed: false,
+			Parent:    parent,
+			Status:    utils.StoreWorkflow(c).Initial,
+			Due:       due,
+			Wait:      wait,
+			Recur:     recur,
+			Tags:      applyTags(nil, tags, nil),
+			Fields:    applyFields(nil, setFields, nil),
+			CreatedAt: now,
+			UpdatedAt: now,
 		}
 
-		c.Todos = append(c.Todos, newTodo)
-		if err = fs.SaveConfig(c); err != nil {
+		if err = s.PutTodo(newTodo); err != nil {
 			fmt.Printf("%sError saving config: %v%s\n", config.Red, err, config.Reset)
 			return
 		}
@@ -78,76 +164,57 @@ var addCmd = &cobra.Command{
 			groupDisplay = "default"
 		}
 
-		fmt.Printf("%sAdded todo [%s%s%s]: %s%s%s\n", config.Green,
-			config.Purple, id, config.Green,
+		fmt.Printf("%sAdded todo [%s%d%s]: %s%s%s\n", config.Green,
+			config.Purple, number, config.Green,
 			config.Bold, task, config.Reset)
 		fmt.Printf("  %sUrgency:%s %s%s%s | %sGroup:%s %s%s%s\n",
 			config.Cyan, config.Reset,
 			urgencyColor, urgencyText, config.Reset,
 			config.Cyan, config.Reset,
 			config.Yellow, groupDisplay, config.Reset)
+		if due != nil {
+			fmt.Printf("  %sDue:%s %s\n", config.Cyan, config.Reset, utils.FormatDue(*due, userSettings.DateFormat, now))
+		}
+		if wait != nil {
+			fmt.Printf("  %sSnoozed until:%s %s\n", config.Cyan, config.Reset, utils.FormatDue(*wait, userSettings.DateFormat, now))
+		}
+		if recur != "" {
+			fmt.Printf("  %sRepeats:%s %s\n", config.Cyan, config.Reset, recur)
+		}
+		if len(newTodo.Tags) > 0 {
+			fmt.Printf("  %sTags:%s %s\n", config.Cyan, config.Reset, formatTags(newTodo.Tags))
+		}
+		if len(newTodo.Fields) > 0 {
+			fmt.Printf("  %sFields:%s %s\n", config.Cyan, config.Reset, strings.Join(formatFields(c.Fields, newTodo.Fields), ", "))
+		}
 	},
 }
 
 var completeCmd = &cobra.Command{
 	Use:   "complete [todo-id]",
 	Short: "Mark todo as completed",
-	Args:  cobra.MinimumNArgs(1),
+	Long: `Mark a todo as completed, moving it to the done state of the workflow.
+This is a shortcut for 'todo status <id> done'.`,
+	Args: cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		id := args[0]
-
-		c, err := fs.GetConfig()
-		if err != nil {
-			fmt.Printf("%sError loading config: %v%s\n", config.Red, err, config.Reset)
-			return
-		}
-
-		for i, todo := range c.Todos {
-			if todo.ID == id {
-				c.Todos[i].Completed = true
-				if err = fs.SaveConfig(c); err != nil {
-					fmt.Printf("%sError saving config: %v%s\n", config.Red, err, config.Reset)
-					return
-				}
-				fmt.Printf("%sCompleted todo [%s%s%s]: %s%s%s\n", config.Green,
-					config.Purple, id, config.Green,
-					config.Bold, todo.Task, config.Reset)
-				return
-			}
-		}
-
-		fmt.Printf("%sTodo with ID '%s' not found%s\n", config.Red, id, config.Reset)
+		recursive, _ := cmd.Flags().GetBool("recursive")
+		changeStatus(args[0], func(w types.Workflow, todo types.Todo) string { return w.Done }, recursive)
 	},
 }
 
 var incompleteCmd = &cobra.Command{
 	Use:   "incomplete [todo-id]",
 	Short: "Mark todo as incomplete",
-	Args:  cobra.MinimumNArgs(1),
+	Long: `Reopen a completed or otherwise closed todo, moving it back to the
+initial state of the workflow. Open todos are left as they are.`,
+	Args: cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		id := args[0]
-
-		c, err := fs.GetConfig()
-		if err != nil {
-			fmt.Printf("%sError loading config: %v%s\n", config.Red, err, config.Reset)
-			return
-		}
-
-		for i, todo := range c.Todos {
-			if todo.ID == id {
-				c.Todos[i].Completed = false
-				if err = fs.SaveConfig(c); err != nil {
-					fmt.Printf("%sError saving config: %v%s\n", config.Red, err, config.Reset)
-					return
-				}
-				fmt.Printf("%sMarked todo [%s%s%s] as incomplete: %s%s%s\n", config.Yellow,
-					config.Purple, id, config.Yellow,
-					config.Bold, todo.Task, config.Reset)
-				return
+		changeStatus(args[0], func(w types.Workflow, todo types.Todo) string {
+			if !todo.Completed {
+				return todo.Status
 			}
-		}
-
-		fmt.Printf("%sTodo with ID '%s' not found%s\n", config.Red, id, config.Reset)
+			return w.Initial
+		}, false)
 	},
 }
 
@@ -156,69 +223,137 @@ var updateCmd = &cobra.Command{
 	Short: "Update a todo",
 	Args:  cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		id := args[0]
+		ref := args[0]
 
 		task, _ := cmd.Flags().GetString("task")
 		urgency, _ := cmd.Flags().GetInt("urgency")
 		group, _ := cmd.Flags().GetString("group")
 		urgencyChanged := cmd.Flags().Changed("urgency")
+		groupChanged := group != ""
+		dueChanged := cmd.Flags().Changed("due")
+		waitChanged := cmd.Flags().Changed("wait")
+
+		tagArgs, _ := cmd.Flags().GetStringArray("tag")
+		addTags, removeTags, err := splitTagArgs(tagArgs)
+		if err != nil {
+			fmt.Printf("%s%v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		setArgs, _ := cmd.Flags().GetStringArray("set")
+
+		due, err := dueFlag(cmd)
+		if err != nil {
+			fmt.Printf("%s%v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		var wait *time.Time
+		if waitChanged {
+			value, _ := cmd.Flags().GetString("wait")
+			wait, err = parseWait(value, time.Now())
+			if err != nil {
+				fmt.Printf("%s%v%s\n", config.Red, err, config.Reset)
+				return
+			}
+		}
 
 		if urgencyChanged && (urgency < 1 || urgency > 5) {
 			fmt.Printf("%sUrgency must be between 1 and 5%s\n", config.Red, config.Reset)
 			return
 		}
 
-		c, err := fs.GetConfig()
+		s, err := fs.Open()
 		if err != nil {
-			fmt.Printf("%sError loading config: %v%s\n", config.Red, err, config.Reset)
+			fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
 			return
 		}
+		defer s.Close()
 
-		for i, todo := range c.Todos {
-			if todo.ID == id {
-				if group != "" {
-					groupExists := false
-					for _, g := range c.Groups {
-						if g.Name == group {
-							groupExists = true
-							break
-						}
-					}
-					if !groupExists {
-						fmt.Printf("%sGroup '%s' not found%s\n", config.Red, group, config.Reset)
-						return
-					}
-				}
+		c, err := s.Load()
+		if err != nil {
+			fmt.Printf("%sError loading config: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
 
-				var updates []string
-				if task != "" {
-					c.Todos[i].Task = task
-					updates = append(updates, fmt.Sprintf("task: %s%s%s", config.Bold, task, config.Reset))
-				}
-				if urgencyChanged {
-					c.Todos[i].Urgency = urgency
-					urgencyText, urgencyColor := utils.GetUrgencyDisplay(urgency)
-					updates = append(updates, fmt.Sprintf("urgency: %s%s%s", urgencyColor, urgencyText, config.Reset))
-				}
-				if group != "" {
-					c.Todos[i].Group = group
-					updates = append(updates, fmt.Sprintf("group: %s%s%s", config.Yellow, group, config.Reset))
-				}
+		todo := resolveTodo(c, ref)
+		if todo == nil {
+			return
+		}
 
-				if err = fs.SaveConfig(c); err != nil {
-					fmt.Printf("%sError saving config: %v%s\n", config.Red, err, config.Reset)
-					return
-				}
+		setFields, unsetFields, err := parseSetArgs(c.Fields, setArgs, time.Now())
+		if err != nil {
+			fmt.Printf("%s%v%s\n", config.Red, err, config.Reset)
+			return
+		}
 
-				fmt.Printf("%sUpdated todo [%s%s%s]%s\n", config.Green, config.Purple, id, config.Green, config.Reset)
-				if len(updates) > 0 {
-					fmt.Printf("  %sChanges:%s %s\n", config.Cyan, config.Reset, strings.Join(updates, ", "))
+		if groupChanged && groupKey(group) != "" {
+			groupExists := false
+			for _, g := range c.Groups {
+				if g.Name == group {
+					groupExists = true
+					break
 				}
+			}
+			if !groupExists {
+				fmt.Printf("%sGroup '%s' not found%s\n", config.Red, group, config.Reset)
 				return
 			}
 		}
 
-		fmt.Printf("%sTodo with ID '%s' not found%s\n", config.Red, id, config.Reset)
+		var updates []string
+		if task != "" {
+			todo.Task = task
+			updates = append(updates, fmt.Sprintf("task: %s%s%s", config.Bold, task, config.Reset))
+		}
+		if urgencyChanged {
+			todo.Urgency = urgency
+			urgencyText, urgencyColor := utils.GetUrgencyDisplay(urgency)
+			updates = append(updates, fmt.Sprintf("urgency: %s%s%s", urgencyColor, urgencyText, config.Reset))
+		}
+		if groupChanged {
+			todo.Group = groupKey(group)
+			updates = append(updates, fmt.Sprintf("group: %s%s%s", config.Yellow, group, config.Reset))
+		}
+		if len(tagArgs) > 0 {
+			todo.Tags = applyTags(todo.Tags, addTags, removeTags)
+			updates = append(updates, fmt.Sprintf("tags: %s%s%s", config.Blue, formatTags(todo.Tags), config.Reset))
+		}
+		if len(setArgs) > 0 {
+			todo.Fields = applyFields(todo.Fields, setFields, unsetFields)
+			fieldsText := "none"
+			if len(todo.Fields) > 0 {
+				fieldsText = strings.Join(formatFields(c.Fields, todo.Fields), ", ")
+			}
+			updates = append(updates, fmt.Sprintf("fields: %s", fieldsText))
+		}
+		if dueChanged {
+			todo.Due = due
+			dueText := "none"
+			if due != nil {
+				dueText = utils.FormatDue(*due, userSettings.DateFormat, time.Now())
+			}
+			updates = append(updates, fmt.Sprintf("due: %s%s%s", config.Yellow, dueText, config.Reset))
+		}
+		if waitChanged {
+			todo.Wait = wait
+			waitText := "none"
+			if wait != nil {
+				waitText = utils.FormatDue(*wait, userSettings.DateFormat, time.Now())
+			}
+			updates = append(updates, fmt.Sprintf("snoozed until: %s%s%s", config.Cyan, waitText, config.Reset))
+		}
+
+		todo.UpdatedAt = time.Now()
+		if err = s.PutTodo(*todo); err != nil {
+			fmt.Printf("%sError saving config: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		fmt.Printf("%sUpdated todo [%s%d%s]%s\n", config.Green, config.Purple, todo.Number, config.Green, config.Reset)
+		if len(updates) > 0 {
+			fmt.Printf("  %sChanges:%s %s\n", config.Cyan, config.Reset, strings.Join(updates, ", "))
+		}
 	},
 }
 
@@ -227,47 +362,142 @@ var deleteCmd = &cobra.Command{
 	Short: "Delete a todo",
 	Args:  cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		id := args[0]
+		ref := args[0]
+		recursive, _ := cmd.Flags().GetBool("recursive")
+		keepChildren, _ := cmd.Flags().GetBool("keep-children")
+
+		s, err := fs.Open()
+		if err != nil {
+			fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+		defer s.Close()
 
-		c, err := fs.GetConfig()
+		c, err := s.Load()
 		if err != nil {
 			fmt.Printf("%sError loading config: %v%s\n", config.Red, err, config.Reset)
 			return
 		}
 
-		var newTodos []types.Todo
-		var deletedTodo *types.Todo
+		deletedTodo := resolveTodo(c, ref)
+		if deletedTodo == nil {
+			return
+		}
+		id, number := deletedTodo.ID, deletedTodo.Number
+
+		children := subtasks(c.Todos, id)
+		mode := ""
+		if len(children) > 0 {
+			switch {
+			case recursive:
+				mode = childrenDelete
+			case keepChildren:
+				mode = childrenKeep
+			case isTerminal(os.Stdin):
+				mode = askChildren(len(children))
+			default:
+				fmt.Printf("%sTodo [%d] has %d subtask(s), use --recursive to delete them or --keep-children to keep them%s\n",
+					config.Red, number, len(children), config.Reset)
+				return
+			}
+			if mode == "" {
+				fmt.Println("Cancelled")
+				return
+			}
+		}
 
-		for _, todo := range c.Todos {
-			if todo.ID != id {
-				newTodos = append(newTodos, todo)
-			} else {
-				deletedTodo = &todo
+		deleted := map[string]bool{id: true}
+		if mode == childrenDelete {
+			for _, childID := range children {
+				deleted[childID] = true
 			}
 		}
+		isDeleted := func(ref string) bool { return deleted[ref] }
 
-		if deletedTodo == nil {
-			fmt.Printf("%sTodo with ID '%s' not found%s\n", config.Red, id, config.Reset)
-			return
+		parent := deletedTodo.Parent
+		task := deletedTodo.Task
+		now := time.Now()
+		changed := false
+		kept := c.Todos[:0:0]
+		for _, todo := range c.Todos {
+			if deleted[todo.ID] {
+				continue
+			}
+			if todo.Parent == id {
+				todo.Parent = parent
+				todo.UpdatedAt = now
+				changed = true
+			}
+			// Dependencies on deleted todos would block nothing and
+			// point nowhere, so they go in the same write.
+			if slices.ContainsFunc(todo.DependsOn, isDeleted) {
+				todo.DependsOn = slices.DeleteFunc(slices.Clone(todo.DependsOn), isDeleted)
+				if len(todo.DependsOn) == 0 {
+					todo.DependsOn = nil
+				}
+				todo.UpdatedAt = now
+				changed = true
+			}
+			kept = append(kept, todo)
 		}
+		c.Todos = kept
 
-		c.Todos = newTodos
-		if err = fs.SaveConfig(c); err != nil {
+		if changed || len(deleted) > 1 {
+			err = s.Save(c)
+		} else {
+			err = s.DeleteTodo(id)
+		}
+		if err != nil {
 			fmt.Printf("%sError saving config: %v%s\n", config.Red, err, config.Reset)
 			return
 		}
 
-		fmt.Printf("%sDeleted todo [%s%s%s]: %s%s%s\n", config.Red,
-			config.Purple, id, config.Red,
-			config.Bold, deletedTodo.Task, config.Reset)
+		fmt.Printf("%sDeleted todo [%s%d%s]: %s%s%s\n", config.Red,
+			config.Purple, number, config.Red,
+			config.Bold, task, config.Reset)
+		switch mode {
+		case childrenDelete:
+			fmt.Printf("  %sAlso deleted %d subtask(s)%s\n", config.Red, len(children), config.Reset)
+		case childrenKeep:
+			fmt.Printf("  %sKept its subtasks, moved up a level%s\n", config.Cyan, config.Reset)
+		}
 	},
 }
 
+// dueFlag parses --due. It returns nil when the flag is empty or "none".
+func dueFlag(cmd *cobra.Command) (*time.Time, error) {
+	value, _ := cmd.Flags().GetString("due")
+	if value == "" || value == "none" {
+		return nil, nil
+	}
+
+	due, err := utils.ParseDue(value, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &due, nil
+}
+
 func init() {
-	addCmd.Flags().IntP("urgency", "u", 1, "Set urgency level (1-5)")
+	addCmd.Flags().IntP("urgency", "u", 0, "Set urgency level (1-5, defaults to the default_urgency setting)")
 	addCmd.Flags().StringP("group", "g", "", "Assign to group")
+	addCmd.Flags().String("due", "", "Set due date, e.g. 2026-10-24, tomorrow, fri, 'next monday 5pm', 'in 3 days', eom")
+	addCmd.Flags().String("wait", "", "Hide the todo from the default list until then, e.g. 3d, mon, 2026-11-02")
+	addCmd.Flags().StringArray("tag", nil, "Add a tag, can be repeated")
+	addCmd.Flags().StringArray("set", nil, "Set a custom field, e.g. --set story_points=3, can be repeated")
+	addCmd.Flags().String("parent", "", "Add as a subtask of this todo (inherits its group)")
+	addCmd.Flags().String("every", "", "Repeat the todo, e.g. daily, weekday, mon, 2w, 6mo, 'monthly on 1st'")
+
+	completeCmd.Flags().BoolP("recursive", "r", false, "Also complete open subtasks")
+
+	deleteCmd.Flags().BoolP("recursive", "r", false, "Also delete subtasks")
+	deleteCmd.Flags().Bool("keep-children", false, "Keep subtasks, moving them up a level")
 
 	updateCmd.Flags().StringP("task", "t", "", "Update todo task")
 	updateCmd.Flags().IntP("urgency", "u", 0, "Update urgency level (1-5)")
 	updateCmd.Flags().StringP("group", "g", "", "Update group assignment")
+	updateCmd.Flags().String("due", "", "Update due date, 'none' removes it")
+	updateCmd.Flags().String("wait", "", "Hide the todo from the default list until then, 'none' shows it again")
+	updateCmd.Flags().StringArray("tag", nil, "Add a tag, or remove it with a leading '-' (--tag=-old), can be repeated")
+	updateCmd.Flags().StringArray("set", nil, "Set a custom field, or unset it with an empty value (--set env=), can be repeated")
 }
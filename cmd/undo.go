@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/dorukozerr/todo-cli/internal/config"
+	"github.com/dorukozerr/todo-cli/internal/fs"
+	"github.com/dorukozerr/todo-cli/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var undoCmd = &cobra.Command{
+	Use:   "undo [n]",
+	Short: "Undo the last n changes",
+	Long: `Undo the last n changes (default 1).
+
+Every change made by add, complete, incomplete, update, delete and group
+is recorded in a history file next to the store. Undo refuses to overwrite
+todos that were changed again afterwards unless --force is given.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runHistoryReplay(cmd, args, true)
+	},
+}
+
+var redoCmd = &cobra.Command{
+	Use:   "redo [n]",
+	Short: "Redo the last n undone changes",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runHistoryReplay(cmd, args, false)
+	},
+}
+
+func runHistoryReplay(cmd *cobra.Command, args []string, undo bool) {
+	n := 1
+	if len(args) > 0 {
+		var err error
+		n, err = strconv.Atoi(args[0])
+		if err != nil || n < 1 {
+			fmt.Printf("%sCount must be a positive number%s\n", config.Red, config.Reset)
+			return
+		}
+	}
+
+	force, _ := cmd.Flags().GetBool("force")
+
+	s, err := fs.Open()
+	if err != nil {
+		fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
+		return
+	}
+	defer s.Close()
+
+	replay, verb := s.Redo, "Redid"
+	if undo {
+		replay, verb = s.Undo, "Undid"
+	}
+
+	ops, err := replay(n, force)
+	if err != nil {
+		fmt.Printf("%s%v%s\n", config.Red, err, config.Reset)
+		return
+	}
+
+	for _, op := range ops {
+		fmt.Printf("%s%s%s %s%s%s (%s)\n",
+			config.Green, verb, config.Reset,
+			config.Bold, op.Command, config.Reset,
+			utils.FormatDateTime(op.Time, userSettings.DateFormat))
+
+		from, to := op.Before, op.After
+		if undo {
+			from, to = op.After, op.Before
+		}
+		for _, change := range describeChanges(&from, &to) {
+			fmt.Printf("  %s\n", change)
+		}
+	}
+}
+
+func init() {
+	undoCmd.Flags().BoolP("force", "f", false, "Undo even if the todos were changed since")
+	redoCmd.Flags().BoolP("force", "f", false, "Redo even if the todos were changed since")
+}
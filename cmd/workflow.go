@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dorukozerr/todo-cli/internal/config"
+	"github.com/dorukozerr/todo-cli/internal/fs"
+	"github.com/dorukozerr/todo-cli/internal/types"
+	"github.com/dorukozerr/todo-cli/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var workflowCmd = &cobra.Command{
+	Use:   "workflow",
+	Short: "Show the states todos can be in",
+	Long: `Show the states of the store's workflow and the moves between them:
+- workflow: Show the states, in the order 'todo list' sorts by
+- workflow edit: Edit the workflow as JSON in $EDITOR
+- workflow reset: Go back to the default workflow
+
+The workflow is part of the store, so everyone sharing it uses the same
+states. Todos in a closed state count as completed.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		s, err := fs.Open()
+		if err != nil {
+			fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+		defer s.Close()
+
+		c, err := s.Load()
+		if err != nil {
+			fmt.Printf("%sError loading config: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		w := utils.StoreWorkflow(c)
+		source := "custom"
+		if c.Workflow == nil {
+			source = "default"
+		}
+
+		counts := make(map[string]int)
+		for _, todo := range c.Todos {
+			counts[todo.Status]++
+		}
+
+		fmt.Printf("%sWorkflow (%s):%s\n", config.Blue+config.Bold, source, config.Reset)
+		for _, state := range w.States {
+			var flags []string
+			if state.Name == w.Initial {
+				flags = append(flags, "initial")
+			}
+			if state.Name == w.Done {
+				flags = append(flags, "done")
+			}
+			if state.Closed {
+				flags = append(flags, "closed")
+			}
+
+			moves := "any state"
+			if targets, ok := w.Transitions[state.Name]; ok {
+				moves = strings.Join(targets, ", ")
+				if moves == "" {
+					moves = "nowhere"
+				}
+			}
+
+			fmt.Printf("  %s%s %-14s%s %-22s %s-> %-20s%s %d todo(s)\n",
+				statusColor(w, state), statusMark(w, state.Name), state.Name, config.Reset,
+				strings.Join(flags, ", "),
+				config.Cyan, moves, config.Reset, counts[state.Name])
+		}
+	},
+}
+
+var workflowEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit the workflow as JSON in $EDITOR",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		original, err := readWorkflow()
+		if err != nil {
+			fmt.Printf("%sError loading config: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		data, err := json.MarshalIndent(original, "", "  ")
+		if err != nil {
+			fmt.Printf("%sError encoding workflow: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		edited, err := editText(string(data)+"\n", "todo-workflow-*.json")
+		if err != nil {
+			fmt.Printf("%sError running editor: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		var w types.Workflow
+		if err := json.Unmarshal([]byte(edited), &w); err != nil {
+			fmt.Printf("%sInvalid workflow JSON, nothing was saved: %v. Your version:%s\n\n%s\n", config.Red, err, config.Reset, edited)
+			return
+		}
+		if sameWorkflow(w, original) {
+			fmt.Printf("%sWorkflow unchanged%s\n", config.Yellow, config.Reset)
+			return
+		}
+
+		s, err := fs.Open()
+		if err != nil {
+			fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+		defer s.Close()
+
+		c, err := s.Load()
+		if err != nil {
+			fmt.Printf("%sError loading config: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		if !sameWorkflow(utils.StoreWorkflow(c), original) {
+			fmt.Printf("%sThe workflow was changed while you were editing it, nothing was saved. Your version:%s\n\n%s\n",
+				config.Red, config.Reset, edited)
+			return
+		}
+
+		if err := setWorkflow(s, c, &w); err != nil {
+			fmt.Printf("%s%v. Your version:%s\n\n%s\n", config.Red, err, config.Reset, edited)
+			return
+		}
+
+		fmt.Printf("%sSaved the workflow (%d states)%s\n", config.Green, len(w.States), config.Reset)
+	},
+}
+
+var workflowResetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Go back to the default workflow",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		s, err := fs.Open()
+		if err != nil {
+			fmt.Printf("%sError opening store: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+		defer s.Close()
+
+		c, err := s.Load()
+		if err != nil {
+			fmt.Printf("%sError loading config: %v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		if c.Workflow == nil {
+			fmt.Printf("%sThe store already uses the default workflow%s\n", config.Yellow, config.Reset)
+			return
+		}
+
+		if err := setWorkflow(s, c, nil); err != nil {
+			fmt.Printf("%s%v%s\n", config.Red, err, config.Reset)
+			return
+		}
+
+		fmt.Printf("%sSwitched back to the default workflow%s\n", config.Green, config.Reset)
+	},
+}
+
+func readWorkflow() (types.Workflow, error) {
+	s, err := fs.Open()
+	if err != nil {
+		return types.Workflow{}, err
+	}
+	defer s.Close()
+
+	c, err := s.Load()
+	if err != nil {
+		return types.Workflow{}, err
+	}
+	return utils.StoreWorkflow(c), nil
+}
+
+// setWorkflow validates and saves a new workflow, nil meaning the default
+// one. It refuses to drop states that todos are still in, and updates the
+// completed flag of todos whose state became open or closed.
+func setWorkflow(s *fs.Handle, c *types.Config, w *types.Workflow) error {
+	next := utils.DefaultWorkflow()
+	if w != nil {
+		next = *w
+	}
+	if err := utils.ValidateWorkflow(next); err != nil {
+		return fmt.Errorf("invalid workflow, nothing was saved: %w", err)
+	}
+
+	now := time.Now()
+	for i := range c.Todos {
+		todo := &c.Todos[i]
+		state, ok := utils.FindState(next, todo.Status)
+		if !ok {
+			return fmt.Errorf("todo [%d] is %s, which the new workflow does not have, move it first", todo.Number, todo.Status)
+		}
+		if state.Closed != todo.Completed {
+			applyStatus(todo, state, now)
+		}
+	}
+
+	c.Workflow = w
+	return s.Save(c)
+}
+
+func sameWorkflow(a, b types.Workflow) bool {
+	aData, aErr := json.Marshal(a)
+	bData, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && string(aData) == string(bData)
+}
+
+func init() {
+	workflowCmd.AddCommand(workflowEditCmd)
+	workflowCmd.AddCommand(workflowResetCmd)
+}
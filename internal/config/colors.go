@@ -1,6 +1,6 @@
 package config
 
-const (
+var (
 	Reset     = "\033[0m"
 	Red       = "\033[31m"
 	Green     = "\033[32m"
@@ -13,5 +13,13 @@ const (
 	BgGreen   = "\033[42m"
 	BgYellow  = "\033[43m"
 	Bold      = "\033[1m"
+	Dim       = "\033[2m"
 	Underline = "\033[4m"
 )
+
+// DisableColors blanks every escape sequence, for output that is not a
+// terminal or when the user turned colors off.
+func DisableColors() {
+	Reset, Red, Green, Yellow, Blue, Purple, Cyan, White = "", "", "", "", "", "", "", ""
+	BgRed, BgGreen, BgYellow, Bold, Dim, Underline = "", "", "", "", "", ""
+}
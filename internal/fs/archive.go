@@ -0,0 +1,232 @@
+package fs
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/dorukozerr/todo-cli/internal/types"
+	"github.com/dorukozerr/todo-cli/internal/utils"
+)
+
+func archivePath(configPath string) string {
+	return configPath + ".archive"
+}
+
+// LoadArchive reads the archive beside the store. Archived todos go through
+// the same schema migrations as the store when they are read.
+func (h *Handle) LoadArchive() (*types.Archive, error) {
+	archive := &types.Archive{SchemaVersion: SchemaVersion, Todos: []types.ArchivedTodo{}}
+
+	data, err := os.ReadFile(archivePath(h.path))
+	if os.IsNotExist(err) {
+		return archive, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = decodeBlob(h.path, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]any
+
+	err = json.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := migrationContext{configPath: h.path, dryRun: true}
+	if documentVersion(doc) < SchemaVersion {
+		config, err := h.Store.Load()
+		if err != nil {
+			return nil, err
+		}
+		ctx.storeIDs = make(map[string]string)
+		for _, todo := range config.Todos {
+			ctx.storeIDs[strconv.Itoa(todo.Number)] = todo.ID
+		}
+	}
+
+	report, err := migrateDocument(doc, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, archive)
+	if err != nil {
+		return nil, err
+	}
+
+	// Migrations can hand out new IDs, so the migrated archive is saved
+	// right away to keep them the same on the next read.
+	if len(report.Steps) > 0 {
+		if err := h.saveArchive(archive); err != nil {
+			return nil, err
+		}
+	}
+
+	return archive, nil
+}
+
+func (h *Handle) saveArchive(archive *types.Archive) error {
+	archive.SchemaVersion = SchemaVersion
+
+	data, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	data, err = encodeBlob(h.path, data)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(archivePath(h.path), data, storeFileMode)
+}
+
+// Archive moves every todo matching match out of the store into the archive.
+// Dependencies on archived todos are kept, so unarchiving brings them back.
+// Subtasks left in the store move up to their closest ancestor that stays,
+// the way deleting a parent keeps its children, and are returned as moved.
+func (h *Handle) Archive(match func(todo types.Todo) bool) (archived []types.ArchivedTodo, moved []types.Todo, err error) {
+	before, err := h.beforeWrite()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	archive, err := h.LoadArchive()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	after := cloneConfig(before)
+	after.Todos = after.Todos[:0:0]
+
+	now := time.Now()
+	parents := make(map[string]string)
+	for _, todo := range before.Todos {
+		if match(todo) {
+			archived = append(archived, types.ArchivedTodo{Todo: todo, ArchivedAt: now})
+			parents[todo.ID] = todo.Parent
+		} else {
+			after.Todos = append(after.Todos, todo)
+		}
+	}
+
+	if len(archived) == 0 {
+		return nil, nil, nil
+	}
+
+	for i := range after.Todos {
+		todo := &after.Todos[i]
+		if _, ok := parents[todo.Parent]; !ok {
+			continue
+		}
+		// Parents are walked with a limit so a cycle from a hand edit
+		// cannot loop forever.
+		parent := todo.Parent
+		for range len(parents) {
+			next, ok := parents[parent]
+			if !ok {
+				break
+			}
+			parent = next
+		}
+		if _, ok := parents[parent]; ok {
+			parent = ""
+		}
+		todo.Parent = parent
+		todo.UpdatedAt = now
+		moved = append(moved, *todo)
+	}
+
+	putArchived(archive, archived...)
+	if err := h.saveArchive(archive); err != nil {
+		return nil, nil, err
+	}
+	if err := h.write(after); err != nil {
+		return nil, nil, err
+	}
+
+	op := newOperation(before, after)
+	op.Archived = archived
+	return archived, moved, h.recordOperation(op)
+}
+
+// Unarchive moves the todo ref points to, a number or a unique UUID prefix,
+// back from the archive into the store. If its number has been taken in the
+// meantime it gets a new one, so the number it had in the archive is
+// returned as well.
+func (h *Handle) Unarchive(ref string) (*types.Todo, int, error) {
+	before, err := h.beforeWrite()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	archive, err := h.LoadArchive()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	todos := make([]types.Todo, len(archive.Todos))
+	for i, entry := range archive.Todos {
+		todos[i] = entry.Todo
+	}
+	i, err := resolveRef(todos, ref)
+	if err != nil {
+		return nil, 0, err
+	}
+	entry := &archive.Todos[i]
+	id := entry.ID
+
+	todo := entry.Todo
+	for _, other := range before.Todos {
+		if other.Number == todo.Number {
+			todo.Number = utils.NextTodoNumber(*before)
+			break
+		}
+	}
+
+	unarchived := *entry
+	removeArchived(archive, id)
+
+	after := cloneConfig(before)
+	putTodo(after, todo)
+
+	if err := h.write(after); err != nil {
+		return nil, 0, err
+	}
+	if err := h.saveArchive(archive); err != nil {
+		return nil, 0, err
+	}
+
+	op := newOperation(before, after)
+	op.Unarchived = []types.ArchivedTodo{unarchived}
+	return &todo, unarchived.Number, h.recordOperation(op)
+}
+
+func putArchived(archive *types.Archive, entries ...types.ArchivedTodo) {
+	for _, entry := range entries {
+		removeArchived(archive, entry.ID)
+		archive.Todos = append(archive.Todos, entry)
+	}
+}
+
+func removeArchived(archive *types.Archive, id string) {
+	kept := make([]types.ArchivedTodo, 0, len(archive.Todos))
+	for _, entry := range archive.Todos {
+		if entry.ID != id {
+			kept = append(kept, entry)
+		}
+	}
+	archive.Todos = kept
+}
@@ -0,0 +1,223 @@
+package fs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dorukozerr/todo-cli/internal/types"
+)
+
+const (
+	snapshotTimeFormat = "20060102T150405.000000"
+	snapshotManual     = "-manual"
+)
+
+type Snapshot struct {
+	ID     string
+	Path   string
+	Time   time.Time
+	Manual bool
+	Size   int64
+}
+
+func backupDir(configPath string) string {
+	return configPath + ".backups"
+}
+
+// Snapshots lists the snapshots of this store, newest first.
+func (h *Handle) Snapshots() ([]Snapshot, error) {
+	entries, err := os.ReadDir(backupDir(h.path))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []Snapshot
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() {
+			continue
+		}
+
+		stamp, manual := strings.CutSuffix(id, snapshotManual)
+		t, err := time.ParseInLocation(snapshotTimeFormat, stamp, time.Local)
+		if err != nil {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		snapshots = append(snapshots, Snapshot{
+			ID:     id,
+			Path:   filepath.Join(backupDir(h.path), entry.Name()),
+			Time:   t,
+			Manual: manual,
+			Size:   info.Size(),
+		})
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Time.After(snapshots[j].Time)
+	})
+
+	return snapshots, nil
+}
+
+// CreateSnapshot saves the current state as a manual snapshot, which is never
+// removed by rotation.
+func (h *Handle) CreateSnapshot() (*Snapshot, error) {
+	config, err := h.Load()
+	if err != nil {
+		return nil, err
+	}
+	return h.takeSnapshot(config, true)
+}
+
+// FindSnapshot resolves a snapshot ID or a unique prefix of one.
+func (h *Handle) FindSnapshot(id string) (*Snapshot, error) {
+	snapshots, err := h.Snapshots()
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Snapshot
+	for _, snapshot := range snapshots {
+		if snapshot.ID == id {
+			return &snapshot, nil
+		}
+		if strings.HasPrefix(snapshot.ID, id) {
+			matches = append(matches, snapshot)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("snapshot '%s' not found", id)
+	case 1:
+		return &matches[0], nil
+	default:
+		return nil, fmt.Errorf("snapshot '%s' is ambiguous (%d matches)", id, len(matches))
+	}
+}
+
+// LoadSnapshot reads a snapshot, migrating it in memory if it was written by
+// an older schema.
+func (h *Handle) LoadSnapshot(snapshot *Snapshot) (*types.Config, error) {
+	data, err := os.ReadFile(snapshot.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = decodeBlob(h.path, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]any
+
+	err = json.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := migrateDocument(doc, migrationContext{dryRun: true}); err != nil {
+		return nil, err
+	}
+
+	return decodeDocument(doc)
+}
+
+func (h *Handle) takeSnapshot(config *types.Config, manual bool) (*Snapshot, error) {
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := h.Snapshots()
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) > 0 && !manual {
+		latest, err := os.ReadFile(snapshots[0].Path)
+		if err == nil {
+			latest, err = decodeBlob(h.path, latest)
+		}
+		if err == nil && bytes.Equal(latest, data) {
+			return &snapshots[0], nil
+		}
+	}
+
+	err = os.MkdirAll(backupDir(h.path), 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	id := now.Format(snapshotTimeFormat)
+	if manual {
+		id += snapshotManual
+	}
+	path := filepath.Join(backupDir(h.path), id+".json")
+
+	encoded, err := encodeBlob(h.path, data)
+	if err != nil {
+		return nil, err
+	}
+
+	err = writeFileAtomic(path, encoded, storeFileMode)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Snapshot{ID: id, Path: path, Time: now, Manual: manual, Size: int64(len(encoded))}, nil
+}
+
+// rotateSnapshots keeps the newest BackupKeep automatic snapshots plus the
+// newest one of each of the last BackupDays days. Manual snapshots are kept.
+func (h *Handle) rotateSnapshots() error {
+	snapshots, err := h.Snapshots()
+	if err != nil {
+		return err
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -h.settings.BackupDays)
+	days := make(map[string]bool)
+	recent := 0
+
+	for _, snapshot := range snapshots {
+		if snapshot.Manual {
+			continue
+		}
+
+		keep := false
+		if recent < h.settings.BackupKeep {
+			recent++
+			keep = true
+		}
+
+		day := snapshot.Time.Format("2006-01-02")
+		if snapshot.Time.After(cutoff) && !days[day] {
+			days[day] = true
+			keep = true
+		}
+
+		if !keep {
+			if err := os.Remove(snapshot.Path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
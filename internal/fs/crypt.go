@@ -0,0 +1,391 @@
+package fs
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	EnvPassphrase = "TODO_CLI_PASSPHRASE"
+
+	cipherName     = "aes-256-gcm"
+	kdfName        = "pbkdf2-sha256"
+	kdfIterations  = 600000
+	kdfSaltSize    = 16
+	storeFileMode  = 0600
+	encryptedField = `"encrypted"`
+)
+
+var (
+	ErrWrongPassphrase = errors.New("wrong passphrase, or the encrypted store is corrupted")
+	ErrNoPassphrase    = fmt.Errorf("store is encrypted, set $%s or run in a terminal to be prompted", EnvPassphrase)
+)
+
+// PassphrasePrompt asks the user for the passphrase of an encrypted store.
+// It is set by the command layer; without it only $TODO_CLI_PASSPHRASE is
+// used.
+var PassphrasePrompt func(configPath string) (string, error)
+
+// envelope is the on-disk form of every encrypted blob: the store file
+// itself, and each line of its journal and history.
+type envelope struct {
+	Encrypted  string `json:"encrypted"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+type storeCipher struct {
+	salt       []byte
+	iterations int
+	aead       cipher.AEAD
+}
+
+var (
+	storeCiphers = make(map[string]*storeCipher)
+	passphrases  = make(map[string]string)
+	derivedKeys  = make(map[string]cipher.AEAD)
+)
+
+func isEnvelope(data []byte) bool {
+	trimmed := bytes.TrimSpace(data)
+	return bytes.HasPrefix(trimmed, []byte("{"+encryptedField))
+}
+
+func passphraseFor(configPath string) (string, error) {
+	if passphrase, ok := passphrases[configPath]; ok {
+		return passphrase, nil
+	}
+
+	passphrase := os.Getenv(EnvPassphrase)
+	if passphrase == "" {
+		if PassphrasePrompt == nil {
+			return "", ErrNoPassphrase
+		}
+
+		var err error
+		passphrase, err = PassphrasePrompt(configPath)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	passphrases[configPath] = passphrase
+	return passphrase, nil
+}
+
+func deriveAEAD(passphrase string, salt []byte, iterations int) (cipher.AEAD, error) {
+	cacheKey := fmt.Sprintf("%x:%d:%s", salt, iterations, passphrase)
+	if aead, ok := derivedKeys[cacheKey]; ok {
+		return aead, nil
+	}
+
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	derivedKeys[cacheKey] = aead
+	return aead, nil
+}
+
+func newStoreCipher(passphrase string) (*storeCipher, error) {
+	salt := make([]byte, kdfSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	aead, err := deriveAEAD(passphrase, salt, kdfIterations)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storeCipher{salt: salt, iterations: kdfIterations, aead: aead}, nil
+}
+
+// cipherFor reports how the store at configPath is encrypted, or nil when it
+// is plaintext. The answer is read from the store file once per process.
+func cipherFor(configPath string) (*storeCipher, error) {
+	if c, ok := storeCiphers[configPath]; ok {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !isEnvelope(data) {
+		storeCiphers[configPath] = nil
+		return nil, nil
+	}
+
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, err
+	}
+
+	passphrase, err := passphraseFor(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := deriveAEAD(passphrase, env.Salt, env.Iterations)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &storeCipher{salt: env.Salt, iterations: env.Iterations, aead: aead}
+	storeCiphers[configPath] = c
+	return c, nil
+}
+
+// encodeBlob encrypts data when the store at configPath is encrypted and
+// returns it unchanged otherwise.
+func encodeBlob(configPath string, data []byte) ([]byte, error) {
+	c, err := cipherFor(configPath)
+	if err != nil || c == nil {
+		return data, err
+	}
+	return c.seal(data)
+}
+
+// decodeBlob decrypts data if it is an encrypted envelope and returns it
+// unchanged otherwise.
+func decodeBlob(configPath string, data []byte) ([]byte, error) {
+	if !isEnvelope(data) {
+		return data, nil
+	}
+
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, err
+	}
+	if env.Encrypted != cipherName || env.KDF != kdfName {
+		return nil, fmt.Errorf("unsupported encryption %s/%s", env.Encrypted, env.KDF)
+	}
+
+	passphrase, err := passphraseFor(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := deriveAEAD(passphrase, env.Salt, env.Iterations)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := aead.Open(nil, env.Nonce, env.Ciphertext, nil)
+	if err != nil {
+		delete(passphrases, configPath)
+		return nil, ErrWrongPassphrase
+	}
+
+	return plaintext, nil
+}
+
+func (c *storeCipher) seal(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(envelope{
+		Encrypted:  cipherName,
+		KDF:        kdfName,
+		Iterations: c.iterations,
+		Salt:       c.salt,
+		Nonce:      nonce,
+		Ciphertext: c.aead.Seal(nil, nonce, plaintext, nil),
+	})
+}
+
+func (h *Handle) Encrypted() (bool, error) {
+	c, err := cipherFor(h.path)
+	return c != nil, err
+}
+
+// Encrypt converts the store, its archive, history, snapshots and migration
+// backups to encrypted form under passphrase.
+func (h *Handle) Encrypt(passphrase string) error {
+	c, err := newStoreCipher(passphrase)
+	if err != nil {
+		return err
+	}
+	return h.reencode(c, passphrase)
+}
+
+// Decrypt converts the store, its archive, history, snapshots and migration
+// backups back to plaintext.
+func (h *Handle) Decrypt() error {
+	return h.reencode(nil, "")
+}
+
+func (h *Handle) reencode(to *storeCipher, passphrase string) error {
+	config, err := h.Store.Load()
+	if err != nil {
+		return err
+	}
+
+	historyLines, err := readDecodedLines(h.path, historyPath(h.path))
+	if err != nil {
+		return err
+	}
+
+	archive, err := h.LoadArchive()
+	if err != nil {
+		return err
+	}
+	_, err = os.Stat(archivePath(h.path))
+	hasArchive := err == nil
+
+	snapshots, err := h.Snapshots()
+	if err != nil {
+		return err
+	}
+	snapshotData := make([][]byte, len(snapshots))
+	for i, snapshot := range snapshots {
+		data, err := os.ReadFile(snapshot.Path)
+		if err != nil {
+			return err
+		}
+		snapshotData[i], err = decodeBlob(h.path, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	backups, err := migrationBackups(h.path)
+	if err != nil {
+		return err
+	}
+	isJournal := func(path string) bool {
+		return strings.HasPrefix(filepath.Base(path), filepath.Base(journalPath(h.path)))
+	}
+	backupData := make([][][]byte, len(backups))
+	for i, path := range backups {
+		if isJournal(path) {
+			backupData[i], err = readDecodedLines(h.path, path)
+		} else {
+			var data []byte
+			data, err = os.ReadFile(path)
+			if err == nil {
+				data, err = decodeBlob(h.path, data)
+			}
+			backupData[i] = [][]byte{data}
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	storeCiphers[h.path] = to
+	if to != nil {
+		passphrases[h.path] = passphrase
+	}
+
+	if err := h.Store.Save(config); err != nil {
+		return err
+	}
+
+	if hasArchive {
+		if err := h.saveArchive(archive); err != nil {
+			return err
+		}
+	}
+
+	if historyLines != nil {
+		if err := writeEncodedLines(h.path, historyPath(h.path), historyLines); err != nil {
+			return err
+		}
+	}
+
+	for i, snapshot := range snapshots {
+		encoded, err := encodeBlob(h.path, snapshotData[i])
+		if err != nil {
+			return err
+		}
+		if err := writeFileAtomic(snapshot.Path, encoded, storeFileMode); err != nil {
+			return err
+		}
+	}
+
+	for i, path := range backups {
+		if isJournal(path) {
+			err = writeEncodedLines(h.path, path, backupData[i])
+		} else {
+			var encoded []byte
+			encoded, err = encodeBlob(h.path, backupData[i][0])
+			if err == nil {
+				err = writeFileAtomic(path, encoded, storeFileMode)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeEncodedLines(configPath, path string, lines [][]byte) error {
+	var buf bytes.Buffer
+	for _, line := range lines {
+		encoded, err := encodeBlob(configPath, line)
+		if err != nil {
+			return err
+		}
+		buf.Write(encoded)
+		buf.WriteByte('\n')
+	}
+	return writeFileAtomic(path, buf.Bytes(), storeFileMode)
+}
+
+func readDecodedLines(configPath, path string) ([][]byte, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var lines [][]byte
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		decoded, err := decodeBlob(configPath, line)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, decoded)
+	}
+
+	return lines, nil
+}
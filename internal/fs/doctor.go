@@ -0,0 +1,344 @@
+package fs
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dorukozerr/todo-cli/internal/types"
+	"github.com/dorukozerr/todo-cli/internal/utils"
+)
+
+const (
+	ProblemEmptyID            = "empty-id"
+	ProblemDuplicateID        = "duplicate-id"
+	ProblemMissingNumber      = "missing-number"
+	ProblemDuplicateNumber    = "duplicate-number"
+	ProblemInvalidUrgency     = "invalid-urgency"
+	ProblemMissingGroup       = "missing-group"
+	ProblemDuplicateGroup     = "duplicate-group"
+	ProblemMissingGroupID     = "missing-group-id"
+	ProblemUnknownActiveGroup = "unknown-active-group"
+	ProblemMissingParent      = "missing-parent"
+	ProblemMissingDependency  = "missing-dependency"
+	ProblemInvalidWorkflow    = "invalid-workflow"
+	ProblemUnknownStatus      = "unknown-status"
+	ProblemStatusMismatch     = "status-mismatch"
+	ProblemCorruptHistory     = "corrupt-history"
+)
+
+// Problem is one integrity issue found in a store. Code is stable and meant
+// for scripts; Message is for people.
+type Problem struct {
+	Code    string
+	TodoID  string
+	Message string
+	Fix     string
+}
+
+// Diagnose checks config for problems that the commands never create but
+// hand edits and merges can. Dependencies on todos in archive are kept, so
+// they are not reported.
+func Diagnose(config *types.Config, archive *types.Archive) []Problem {
+	var problems []Problem
+
+	groups := make(map[string]bool)
+	for _, group := range config.Groups {
+		if groups[group.Name] {
+			problems = append(problems, Problem{
+				Code:    ProblemDuplicateGroup,
+				Message: fmt.Sprintf("group '%s' is defined more than once", group.Name),
+				Fix:     "keep the first definition",
+			})
+		}
+		groups[group.Name] = true
+
+		if group.ID == "" {
+			problems = append(problems, Problem{
+				Code:    ProblemMissingGroupID,
+				Message: fmt.Sprintf("group '%s' has no ID", group.Name),
+				Fix:     "assign a new UUID",
+			})
+		}
+	}
+
+	workflow := utils.StoreWorkflow(config)
+	if err := utils.ValidateWorkflow(workflow); err != nil {
+		problems = append(problems, Problem{
+			Code:    ProblemInvalidWorkflow,
+			Message: fmt.Sprintf("the store's workflow is invalid: %v", err),
+			Fix:     "go back to the default workflow",
+		})
+		workflow = utils.DefaultWorkflow()
+	}
+
+	exists := make(map[string]bool)
+	for _, todo := range config.Todos {
+		exists[todo.ID] = true
+	}
+	archived := archivedIDs(archive)
+
+	ids := make(map[string]bool)
+	numbers := make(map[int]bool)
+	for _, todo := range config.Todos {
+		if state, ok := utils.FindState(workflow, todo.Status); !ok {
+			problems = append(problems, Problem{
+				Code:    ProblemUnknownStatus,
+				TodoID:  todo.ID,
+				Message: fmt.Sprintf("todo %s has status '%s', which is not in the workflow", todoLabel(todo), todo.Status),
+				Fix:     fmt.Sprintf("set status to %s", repairedStatus(workflow, todo)),
+			})
+		} else if state.Closed != todo.Completed {
+			problems = append(problems, Problem{
+				Code:    ProblemStatusMismatch,
+				TodoID:  todo.ID,
+				Message: fmt.Sprintf("todo %s has status '%s' but completed is %t", todoLabel(todo), todo.Status, todo.Completed),
+				Fix:     fmt.Sprintf("set completed to %t", state.Closed),
+			})
+		}
+
+		if todo.Parent != "" && (todo.Parent == todo.ID || !exists[todo.Parent]) {
+			problems = append(problems, Problem{
+				Code:    ProblemMissingParent,
+				TodoID:  todo.ID,
+				Message: fmt.Sprintf("todo %s is a subtask of '%s', which does not exist", todoLabel(todo), todo.Parent),
+				Fix:     "make it a top-level todo",
+			})
+		}
+
+		for _, depID := range todo.DependsOn {
+			if !exists[depID] && !archived[depID] {
+				problems = append(problems, Problem{
+					Code:    ProblemMissingDependency,
+					TodoID:  todo.ID,
+					Message: fmt.Sprintf("todo %s depends on '%s', which does not exist", todoLabel(todo), depID),
+					Fix:     "drop the dependency",
+				})
+			}
+		}
+
+		if todo.ID == "" {
+			problems = append(problems, Problem{
+				Code:    ProblemEmptyID,
+				Message: fmt.Sprintf("todo '%s' has no ID", todo.Task),
+				Fix:     "assign a new UUID",
+			})
+		} else if ids[todo.ID] {
+			problems = append(problems, Problem{
+				Code:    ProblemDuplicateID,
+				TodoID:  todo.ID,
+				Message: fmt.Sprintf("ID '%s' is used by more than one todo ('%s')", todo.ID, todo.Task),
+				Fix:     "assign a new UUID to the later todo",
+			})
+		}
+		ids[todo.ID] = true
+
+		if todo.Number < 1 {
+			problems = append(problems, Problem{
+				Code:    ProblemMissingNumber,
+				TodoID:  todo.ID,
+				Message: fmt.Sprintf("todo '%s' has no number", todo.Task),
+				Fix:     "assign the next free number",
+			})
+		} else if numbers[todo.Number] {
+			problems = append(problems, Problem{
+				Code:    ProblemDuplicateNumber,
+				TodoID:  todo.ID,
+				Message: fmt.Sprintf("number %d is used by more than one todo ('%s')", todo.Number, todo.Task),
+				Fix:     "assign the next free number to the later todo",
+			})
+		}
+		numbers[todo.Number] = true
+
+		if todo.Urgency < 1 || todo.Urgency > 5 {
+			problems = append(problems, Problem{
+				Code:    ProblemInvalidUrgency,
+				TodoID:  todo.ID,
+				Message: fmt.Sprintf("todo %s has urgency %d, outside 1-5", todoLabel(todo), todo.Urgency),
+				Fix:     fmt.Sprintf("set urgency to %d", clampUrgency(todo.Urgency)),
+			})
+		}
+
+		if todo.Group != "" && !groups[todo.Group] {
+			problems = append(problems, Problem{
+				Code:    ProblemMissingGroup,
+				TodoID:  todo.ID,
+				Message: fmt.Sprintf("todo %s belongs to group '%s', which does not exist", todoLabel(todo), todo.Group),
+				Fix:     "move it to the default group",
+			})
+		}
+	}
+
+	if config.ActiveGroup != "" && !groups[config.ActiveGroup] {
+		problems = append(problems, Problem{
+			Code:    ProblemUnknownActiveGroup,
+			Message: fmt.Sprintf("active group '%s' does not exist", config.ActiveGroup),
+			Fix:     "switch to the default group",
+		})
+	}
+
+	return problems
+}
+
+// Repair fixes every problem Diagnose reports, in place.
+func Repair(config *types.Config, archive *types.Archive) {
+	groups := make(map[string]bool)
+	keptGroups := config.Groups[:0:0]
+	for _, group := range config.Groups {
+		if !groups[group.Name] {
+			if group.ID == "" {
+				group.ID = utils.GenerateUUID()
+			}
+			keptGroups = append(keptGroups, group)
+		}
+		groups[group.Name] = true
+	}
+	config.Groups = keptGroups
+
+	workflow := utils.StoreWorkflow(config)
+	if utils.ValidateWorkflow(workflow) != nil {
+		config.Workflow = nil
+		workflow = utils.DefaultWorkflow()
+	}
+
+	exists := make(map[string]bool)
+	for _, todo := range config.Todos {
+		exists[todo.ID] = true
+	}
+	archived := archivedIDs(archive)
+	for i := range config.Todos {
+		todo := &config.Todos[i]
+		if todo.Parent == todo.ID || !exists[todo.Parent] {
+			todo.Parent = ""
+		}
+		todo.DependsOn = slices.DeleteFunc(todo.DependsOn, func(depID string) bool {
+			return !exists[depID] && !archived[depID]
+		})
+		if len(todo.DependsOn) == 0 {
+			todo.DependsOn = nil
+		}
+	}
+
+	ids := make(map[string]bool)
+	numbers := make(map[int]bool)
+	for i := range config.Todos {
+		todo := &config.Todos[i]
+		if todo.ID == "" || ids[todo.ID] {
+			todo.ID = utils.GenerateUUID()
+		}
+		ids[todo.ID] = true
+
+		if todo.Number < 1 || numbers[todo.Number] {
+			todo.Number = utils.NextTodoNumber(*config)
+		}
+		numbers[todo.Number] = true
+
+		todo.Urgency = clampUrgency(todo.Urgency)
+
+		state, ok := utils.FindState(workflow, todo.Status)
+		if !ok {
+			todo.Status = repairedStatus(workflow, *todo)
+			state, _ = utils.FindState(workflow, todo.Status)
+		}
+		todo.Completed = state.Closed
+
+		if todo.Group != "" && !groups[todo.Group] {
+			todo.Group = ""
+		}
+	}
+
+	if config.ActiveGroup != "" && !groups[config.ActiveGroup] {
+		config.ActiveGroup = ""
+	}
+}
+
+// Doctor diagnoses the store and, when fix is set and there is something to
+// repair, takes a manual snapshot and saves the repaired store.
+func (h *Handle) Doctor(fix bool) ([]Problem, *Snapshot, error) {
+	config, err := h.Load()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	archive, err := h.LoadArchive()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	problems := Diagnose(config, archive)
+	storeProblems := len(problems)
+
+	badLines, err := badHistoryLines(h.path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(badLines) > 0 {
+		problems = append(problems, Problem{
+			Code:    ProblemCorruptHistory,
+			Message: fmt.Sprintf("%s has %d unreadable line(s): %s", historyPath(h.path), len(badLines), formatLines(badLines)),
+			Fix:     "drop the unreadable lines",
+		})
+	}
+
+	if !fix || len(problems) == 0 {
+		return problems, nil, nil
+	}
+
+	snapshot, err := h.CreateSnapshot()
+	if err != nil {
+		return nil, nil, fmt.Errorf("taking a backup before repairing: %w", err)
+	}
+
+	// The history is repaired first so the repair itself can be recorded.
+	if len(badLines) > 0 {
+		if err := dropHistoryLines(h.path, badLines); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if storeProblems > 0 {
+		Repair(config, archive)
+		if err := h.Save(config); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return problems, snapshot, nil
+}
+
+func archivedIDs(archive *types.Archive) map[string]bool {
+	ids := make(map[string]bool)
+	for _, entry := range archive.Todos {
+		ids[entry.ID] = true
+	}
+	return ids
+}
+
+// repairedStatus is the state a todo with an unknown status is moved to,
+// keeping whether it was completed.
+func repairedStatus(workflow types.Workflow, todo types.Todo) string {
+	if todo.Completed {
+		return workflow.Done
+	}
+	return workflow.Initial
+}
+
+func todoLabel(todo types.Todo) string {
+	if todo.Number < 1 {
+		return fmt.Sprintf("'%s'", todo.Task)
+	}
+	return fmt.Sprintf("[%d]", todo.Number)
+}
+
+func formatLines(lines []int) string {
+	parts := make([]string, len(lines))
+	for i, line := range lines {
+		parts[i] = strconv.Itoa(line)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func clampUrgency(urgency int) int {
+	return max(1, min(5, urgency))
+}
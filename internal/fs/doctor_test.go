@@ -0,0 +1,107 @@
+package fs
+
+import (
+	"testing"
+
+	"github.com/dorukozerr/todo-cli/internal/types"
+)
+
+func TestDoctorAcceptsArchivedDependency(t *testing.T) {
+	h := openTestStore(t)
+
+	putTestTodo(t, h, 1, "a")
+	config, err := h.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dep := config.Todos[0]
+	dep.Status, dep.Completed = "done", true
+	if err := h.PutTodo(dep); err != nil {
+		t.Fatal(err)
+	}
+
+	waiting := types.Todo{ID: "b-0000-0000", Number: 2, Task: "b", Urgency: 1, Status: "todo", DependsOn: []string{dep.ID}}
+	if err := h.PutTodo(waiting); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := h.Archive(func(todo types.Todo) bool { return todo.Completed }); err != nil {
+		t.Fatal(err)
+	}
+
+	problems, _, err := h.Doctor(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) > 0 {
+		t.Errorf("problems after archiving a dependency = %+v", problems)
+	}
+
+	config, err = h.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	Repair(config, &types.Archive{})
+	if len(config.Todos[0].DependsOn) != 0 {
+		t.Errorf("repair without the archive kept dependency %v", config.Todos[0].DependsOn)
+	}
+}
+
+func TestArchiveMovesOpenSubtasksUp(t *testing.T) {
+	h := openTestStore(t)
+
+	closed := func(todo types.Todo) types.Todo {
+		todo.Status, todo.Completed = "done", true
+		return todo
+	}
+	root := types.Todo{ID: "a-0000-0000", Number: 1, Task: "a", Urgency: 1, Status: "todo"}
+	parent := closed(types.Todo{ID: "b-0000-0000", Number: 2, Task: "b", Urgency: 1, Parent: root.ID})
+	child := closed(types.Todo{ID: "c-0000-0000", Number: 3, Task: "c", Urgency: 1, Parent: parent.ID})
+	grandchild := types.Todo{ID: "d-0000-0000", Number: 4, Task: "d", Urgency: 1, Status: "todo", Parent: child.ID}
+	orphan := types.Todo{ID: "e-0000-0000", Number: 5, Task: "e", Urgency: 1, Status: "todo", Parent: "f-0000-0000"}
+	closedRoot := closed(types.Todo{ID: "f-0000-0000", Number: 6, Task: "f", Urgency: 1})
+	for _, todo := range []types.Todo{root, parent, child, grandchild, orphan, closedRoot} {
+		if err := h.PutTodo(todo); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	archived, moved, err := h.Archive(func(todo types.Todo) bool { return todo.Completed })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(archived) != 3 || len(moved) != 2 {
+		t.Fatalf("archived %d and moved %d todos, want 3 and 2", len(archived), len(moved))
+	}
+
+	config, err := h.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parents := make(map[string]string)
+	for _, todo := range config.Todos {
+		parents[todo.Task] = todo.Parent
+	}
+	if parents["d"] != root.ID || parents["e"] != "" {
+		t.Errorf("parents after archiving = %v, want d under a and e top-level", parents)
+	}
+
+	problems, _, err := h.Doctor(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) > 0 {
+		t.Errorf("problems after archiving parents = %+v", problems)
+	}
+
+	if _, err := h.Undo(1, false); err != nil {
+		t.Fatal(err)
+	}
+	config, err = h.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if todo, err := findTodo(config, grandchild.ID); err != nil || todo.Parent != child.ID {
+		t.Errorf("undo did not put subtask d back under c")
+	}
+}
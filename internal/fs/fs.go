@@ -2,52 +2,99 @@ package fs
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/dorukozerr/todo-cli/internal/types"
+	"github.com/dorukozerr/todo-cli/internal/utils"
 )
 
-func GetConfig() (*types.Config, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return nil, err
-	}
+const lockTimeout = 10 * time.Second
 
-	configDir := filepath.Join(homeDir, ".config", "todo-cli")
-	configPath := filepath.Join(configDir, "config.json")
-
-	err = os.MkdirAll(configDir, 0755)
+func loadConfig(configPath string, withJournal bool) (*types.Config, error) {
+	err := os.MkdirAll(filepath.Dir(configPath), 0755)
 	if err != nil {
 		return nil, err
 	}
 
 	_, err = os.Stat(configPath)
 	if os.IsNotExist(err) {
-		emptyConfig := &types.Config{
-			Groups:      []types.Group{},
-			ActiveGroup: "",
-			Todos:       []types.Todo{},
-		}
+		emptyConfig := newConfig()
 
-		configData, err := json.MarshalIndent(emptyConfig, "", "  ")
+		err = writeConfigFile(configPath, emptyConfig)
 		if err != nil {
 			return nil, err
 		}
 
-		err = os.WriteFile(configPath, configData, 0644)
+		return emptyConfig, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := readDocument(configPath, withJournal)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := migrateDocument(doc, migrationContext{configPath: configPath})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(report.Steps) > 0 {
+		_, err = writeMigratedDocument(configPath, doc, report.From)
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		return emptyConfig, nil
+	return decodeDocument(doc)
+}
+
+func newConfig() *types.Config {
+	return &types.Config{
+		SchemaVersion: SchemaVersion,
+		NextNumber:    1,
+		Groups:        []types.Group{},
+		ActiveGroup:   "",
+		Todos:         []types.Todo{},
+	}
+}
+
+func readDocument(configPath string, withJournal bool) (map[string]any, error) {
+	configData, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, err
 	}
 
+	configData, err = decodeBlob(configPath, configData)
 	if err != nil {
 		return nil, err
 	}
 
-	configData, err := os.ReadFile(configPath)
+	var doc map[string]any
+
+	err = json.Unmarshal(configData, &doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if withJournal {
+		err = replayJournal(doc, configPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return doc, nil
+}
+
+func decodeDocument(doc map[string]any) (*types.Config, error) {
+	configData, err := json.Marshal(doc)
 	if err != nil {
 		return nil, err
 	}
@@ -62,19 +109,94 @@ func GetConfig() (*types.Config, error) {
 	return &config, nil
 }
 
-func SaveConfig(config *types.Config) error {
-	homeDir, err := os.UserHomeDir()
+// writeConfigFile never writes the active group, which is per-user state
+// kept outside the store so the file can be shared.
+func writeConfigFile(configPath string, config *types.Config) error {
+	stored := *config
+	stored.ActiveGroup = ""
+	stored.NextNumber = utils.NextTodoNumber(stored)
+
+	configData, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	configData, err = encodeBlob(configPath, configData)
 	if err != nil {
 		return err
 	}
 
-	configDir := filepath.Join(homeDir, ".config", "todo-cli")
-	configPath := filepath.Join(configDir, "config.json")
+	return writeFileAtomic(configPath, configData, storeFileMode)
+}
+
+// lock takes an exclusive advisory lock next to the given file and returns
+// the function that releases it.
+func lock(path string) (func(), error) {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	lockPath := path + ".lock"
+	lockFile, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		locked, err := tryLockFile(lockFile)
+		if err != nil {
+			lockFile.Close()
+			return nil, err
+		}
+		if locked {
+			break
+		}
+		if time.Now().After(deadline) {
+			lockFile.Close()
+			return nil, fmt.Errorf("timed out waiting for lock on %s", lockPath)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	return func() {
+		unlockFile(lockFile)
+		lockFile.Close()
+	}, nil
+}
+
+func writeFileAtomic(path string, data []byte, perm os.FileMode) (err error) {
+	dir := filepath.Dir(path)
 
-	configData, err := json.MarshalIndent(config, "", "  ")
+	tmpFile, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
 	if err != nil {
 		return err
 	}
+	tmpPath := tmpFile.Name()
+
+	defer func() {
+		if err != nil {
+			tmpFile.Close()
+			os.Remove(tmpPath)
+		}
+	}()
+
+	if _, err = tmpFile.Write(data); err != nil {
+		return err
+	}
+	if err = tmpFile.Sync(); err != nil {
+		return err
+	}
+	if err = tmpFile.Chmod(perm); err != nil {
+		return err
+	}
+	if err = tmpFile.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmpPath, path); err != nil {
+		return err
+	}
 
-	return os.WriteFile(configPath, configData, 0644)
+	return syncDir(dir)
 }
@@ -0,0 +1,204 @@
+package fs
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dorukozerr/todo-cli/internal/types"
+)
+
+// snapshotInterval is the least time between two automatic snapshots. The
+// undo history records every change, so snapshots only need to be an
+// occasional restore point.
+const snapshotInterval = 10 * time.Minute
+
+// Handle is an open, locked store. Writes made through it are preceded by a
+// snapshot of the previous state when the last one is old enough and
+// recorded in the undo history, and the lock is held until Close.
+type Handle struct {
+	Store
+	path     string
+	settings *types.Settings
+	unlock   func()
+	// current is a private copy of the store as last loaded or written
+	// through this handle. The lock keeps it up to date, so writes can be
+	// recorded without reading the store again.
+	current     *types.Config
+	snapshotted bool
+}
+
+// Open locks the store and returns a handle on the backend selected in
+// settings.
+func Open() (*Handle, error) {
+	settings, err := GetSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	configPath, err := StorePath()
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := lock(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := newBackend(settings.Backend, configPath)
+	if err != nil {
+		unlock()
+		return nil, err
+	}
+
+	return &Handle{Store: store, path: configPath, settings: settings, unlock: unlock}, nil
+}
+
+func (h *Handle) Path() string {
+	return h.path
+}
+
+// Load returns the store with the user's active group filled in.
+func (h *Handle) Load() (*types.Config, error) {
+	config, err := h.Store.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	config.ActiveGroup, err = activeGroup(h.path)
+	if err != nil {
+		return nil, err
+	}
+
+	h.current = deepCopy(config)
+	return config, nil
+}
+
+func (h *Handle) Save(config *types.Config) error {
+	before, err := h.beforeWrite()
+	if err != nil {
+		return err
+	}
+	if err := h.write(config); err != nil {
+		return err
+	}
+	return h.record(before, config)
+}
+
+func (h *Handle) PutTodo(todo types.Todo) error {
+	before, err := h.beforeWrite()
+	if err != nil {
+		return err
+	}
+	if err := h.Store.PutTodo(todo); err != nil {
+		return err
+	}
+
+	after := cloneConfig(before)
+	putTodo(after, todo)
+	h.current = deepCopy(after)
+	return h.record(before, after)
+}
+
+func (h *Handle) DeleteTodo(id string) error {
+	before, err := h.beforeWrite()
+	if err != nil {
+		return err
+	}
+	if err := h.Store.DeleteTodo(id); err != nil {
+		return err
+	}
+
+	after := cloneConfig(before)
+	deleteTodo(after, id)
+	h.current = deepCopy(after)
+	return h.record(before, after)
+}
+
+// ResolveTodo loads the todo a user means by ref, a number or a unique
+// prefix of its UUID.
+func (h *Handle) ResolveTodo(ref string) (*types.Todo, error) {
+	config, err := h.Store.Load()
+	if err != nil {
+		return nil, err
+	}
+	return ResolveTodo(config, ref)
+}
+
+// write saves the document to the backend and the active group to the
+// user's state.
+func (h *Handle) write(config *types.Config) error {
+	if err := h.Store.Save(config); err != nil {
+		return err
+	}
+	h.current = deepCopy(config)
+	return setActiveGroup(h.path, config.ActiveGroup)
+}
+
+func (h *Handle) Close() error {
+	err := h.Store.Close()
+	h.unlock()
+	return err
+}
+
+// beforeWrite returns the state about to be replaced so it can be recorded
+// for undo, loading it only if the handle has not seen it yet. It snapshots
+// that state at most once per handle, and only when the newest automatic
+// snapshot is older than snapshotInterval.
+func (h *Handle) beforeWrite() (*types.Config, error) {
+	if h.current == nil {
+		if _, err := h.Load(); err != nil {
+			return nil, err
+		}
+	}
+	config := h.current
+	if config == nil {
+		return nil, fmt.Errorf("cannot copy the store")
+	}
+
+	if h.snapshotted || (h.settings.BackupKeep <= 0 && h.settings.BackupDays <= 0) {
+		return config, nil
+	}
+	h.snapshotted = true
+
+	snapshots, err := h.Snapshots()
+	if err != nil {
+		return nil, err
+	}
+	for _, snapshot := range snapshots {
+		if snapshot.Manual {
+			continue
+		}
+		if time.Since(snapshot.Time) < snapshotInterval {
+			return config, nil
+		}
+		break
+	}
+
+	if _, err := h.takeSnapshot(config, false); err != nil {
+		return nil, err
+	}
+	return config, h.rotateSnapshots()
+}
+
+// deepCopy copies v through JSON so the copy shares no slices or maps with
+// it, or returns nil if v cannot be encoded.
+func deepCopy[T any](v *T) *T {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var copied T
+	if err := json.Unmarshal(data, &copied); err != nil {
+		return nil
+	}
+	return &copied
+}
+
+func cloneConfig(config *types.Config) *types.Config {
+	clone := *config
+	clone.Groups = append([]types.Group(nil), config.Groups...)
+	clone.Todos = append([]types.Todo(nil), config.Todos...)
+	return &clone
+}
@@ -0,0 +1,599 @@
+package fs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dorukozerr/todo-cli/internal/types"
+)
+
+const (
+	historyDo   = "do"
+	historyUndo = "undo"
+	historyRedo = "redo"
+
+	historyLimit       = 200
+	historyCompactSize = 2 << 20
+)
+
+// Operation is one recorded change to the store. Before and After only hold
+// the todos listed in TodoIDs, the groups and active group when
+// GroupsChanged is set, the workflow when WorkflowChanged is set and the
+// custom fields when FieldsChanged is set, so they can be applied in either
+// direction.
+// Archived and Unarchived are the entries the change added to or took out
+// of the archive.
+type Operation struct {
+	Seq             int                  `json:"seq"`
+	Time            time.Time            `json:"time"`
+	Command         string               `json:"command"`
+	SchemaVersion   int                  `json:"schema_version"`
+	TodoIDs         []string             `json:"todo_ids,omitempty"`
+	GroupsChanged   bool                 `json:"groups_changed,omitempty"`
+	WorkflowChanged bool                 `json:"workflow_changed,omitempty"`
+	FieldsChanged   bool                 `json:"fields_changed,omitempty"`
+	Before          types.Config         `json:"before"`
+	After           types.Config         `json:"after"`
+	Archived        []types.ArchivedTodo `json:"archived,omitempty"`
+	Unarchived      []types.ArchivedTodo `json:"unarchived,omitempty"`
+}
+
+type historyEntry struct {
+	Action string     `json:"action"`
+	Seq    int        `json:"seq,omitempty"`
+	Op     *Operation `json:"op,omitempty"`
+}
+
+type history struct {
+	done   []Operation
+	undone []Operation
+	next   int
+}
+
+func historyPath(configPath string) string {
+	return configPath + ".history"
+}
+
+func readHistory(configPath string) (*history, error) {
+	h := &history{next: 1}
+
+	data, err := os.ReadFile(historyPath(configPath))
+	if os.IsNotExist(err) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	lines := bytes.Split(data, []byte("\n"))
+	for i, line := range lines {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var entry historyEntry
+		line, err := decodeBlob(configPath, line)
+		if err == nil {
+			err = json.Unmarshal(line, &entry)
+		}
+		if errors.Is(err, ErrWrongPassphrase) || errors.Is(err, ErrNoPassphrase) {
+			return nil, err
+		}
+		if err != nil {
+			if i == len(lines)-1 {
+				break
+			}
+			return nil, fmt.Errorf("history %s line %d: %w", historyPath(configPath), i+1, err)
+		}
+
+		switch entry.Action {
+		case historyDo:
+			if entry.Op == nil {
+				continue
+			}
+			h.done = append(h.done, *entry.Op)
+			h.undone = nil
+			if entry.Op.Seq >= h.next {
+				h.next = entry.Op.Seq + 1
+			}
+		case historyUndo:
+			if n := len(h.done); n > 0 && h.done[n-1].Seq == entry.Seq {
+				h.undone = append(h.undone, h.done[n-1])
+				h.done = h.done[:n-1]
+			}
+		case historyRedo:
+			if n := len(h.undone); n > 0 && h.undone[n-1].Seq == entry.Seq {
+				h.done = append(h.done, h.undone[n-1])
+				h.undone = h.undone[:n-1]
+			}
+		}
+	}
+
+	return h, nil
+}
+
+func appendHistory(configPath string, entries ...historyEntry) error {
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		if err := writeHistoryLine(&buf, configPath, entry); err != nil {
+			return err
+		}
+	}
+
+	if err := dropTornTail(historyPath(configPath)); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(historyPath(configPath), os.O_CREATE|os.O_WRONLY|os.O_APPEND, storeFileMode)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(buf.Bytes())
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(historyPath(configPath))
+	if err != nil {
+		return err
+	}
+	if info.Size() >= historyCompactSize {
+		return compactHistory(configPath)
+	}
+	return nil
+}
+
+// dropTornTail cuts off a last line that a crash left without its newline,
+// so the next append starts on a line of its own instead of extending it.
+func dropTornTail(path string) error {
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil || info.Size() == 0 {
+		return err
+	}
+
+	const chunkSize = 64 << 10
+	end := info.Size()
+	for end > 0 {
+		n := min(end, chunkSize)
+		chunk := make([]byte, n)
+		if _, err := file.ReadAt(chunk, end-n); err != nil {
+			return err
+		}
+		if end == info.Size() && chunk[n-1] == '\n' {
+			return nil
+		}
+		if i := bytes.LastIndexByte(chunk, '\n'); i >= 0 {
+			end = end - n + int64(i) + 1
+			break
+		}
+		end -= n
+	}
+
+	if err := file.Truncate(end); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// badHistoryLines returns the line numbers of the history entries that
+// cannot be decoded, a torn last line included.
+func badHistoryLines(configPath string) ([]int, error) {
+	data, err := os.ReadFile(historyPath(configPath))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var bad []int
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var entry historyEntry
+		line, err := decodeBlob(configPath, line)
+		if err == nil {
+			err = json.Unmarshal(line, &entry)
+		}
+		if errors.Is(err, ErrWrongPassphrase) || errors.Is(err, ErrNoPassphrase) {
+			return nil, err
+		}
+		if err != nil {
+			bad = append(bad, i+1)
+		}
+	}
+	return bad, nil
+}
+
+// dropHistoryLines rewrites the history without the given lines.
+func dropHistoryLines(configPath string, bad []int) error {
+	data, err := os.ReadFile(historyPath(configPath))
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 || slices.Contains(bad, i+1) {
+			continue
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	return writeFileAtomic(historyPath(configPath), buf.Bytes(), storeFileMode)
+}
+
+// compactHistory rewrites the history with only the newest historyLimit
+// operations and the current redo stack.
+func compactHistory(configPath string) error {
+	h, err := readHistory(configPath)
+	if err != nil {
+		return err
+	}
+
+	done := h.done
+	if len(done) > historyLimit {
+		done = done[len(done)-historyLimit:]
+	}
+
+	var buf bytes.Buffer
+	write := func(entry historyEntry) error {
+		return writeHistoryLine(&buf, configPath, entry)
+	}
+
+	for i := range done {
+		if err := write(historyEntry{Action: historyDo, Op: &done[i]}); err != nil {
+			return err
+		}
+	}
+	// The redo stack is rebuilt by replaying its operations in the order
+	// they were done and then undoing them newest first. Interleaving the
+	// two would clear the stack on every do.
+	for i := len(h.undone) - 1; i >= 0; i-- {
+		if err := write(historyEntry{Action: historyDo, Op: &h.undone[i]}); err != nil {
+			return err
+		}
+	}
+	for _, op := range h.undone {
+		if err := write(historyEntry{Action: historyUndo, Seq: op.Seq}); err != nil {
+			return err
+		}
+	}
+
+	return writeFileAtomic(historyPath(configPath), buf.Bytes(), storeFileMode)
+}
+
+func writeHistoryLine(buf *bytes.Buffer, configPath string, entry historyEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	data, err = encodeBlob(configPath, data)
+	if err != nil {
+		return err
+	}
+
+	buf.Write(data)
+	buf.WriteByte('\n')
+	return nil
+}
+
+func commandLine() string {
+	return strings.Join(append([]string{"todo"}, os.Args[1:]...), " ")
+}
+
+// newOperation captures the parts of before and after that differ.
+func newOperation(before, after *types.Config) *Operation {
+	op := &Operation{
+		Time:          time.Now(),
+		Command:       commandLine(),
+		SchemaVersion: SchemaVersion,
+	}
+
+	beforeTodos := make(map[string]types.Todo)
+	for _, todo := range before.Todos {
+		beforeTodos[todo.ID] = todo
+	}
+	afterTodos := make(map[string]types.Todo)
+	for _, todo := range after.Todos {
+		afterTodos[todo.ID] = todo
+	}
+
+	for _, todo := range before.Todos {
+		if afterTodo, ok := afterTodos[todo.ID]; !ok || !sameJSON(todo, afterTodo) {
+			op.TodoIDs = append(op.TodoIDs, todo.ID)
+			op.Before.Todos = append(op.Before.Todos, todo)
+			if ok {
+				op.After.Todos = append(op.After.Todos, afterTodo)
+			}
+		}
+	}
+	for _, todo := range after.Todos {
+		if _, ok := beforeTodos[todo.ID]; !ok {
+			op.TodoIDs = append(op.TodoIDs, todo.ID)
+			op.After.Todos = append(op.After.Todos, todo)
+		}
+	}
+
+	if before.ActiveGroup != after.ActiveGroup || !sameJSON(nonNilGroups(before.Groups), nonNilGroups(after.Groups)) {
+		op.GroupsChanged = true
+		op.Before.Groups = before.Groups
+		op.Before.ActiveGroup = before.ActiveGroup
+		op.After.Groups = after.Groups
+		op.After.ActiveGroup = after.ActiveGroup
+	}
+
+	if !sameJSON(before.Workflow, after.Workflow) {
+		op.WorkflowChanged = true
+		op.Before.Workflow = before.Workflow
+		op.After.Workflow = after.Workflow
+	}
+
+	if !sameJSON(before.Fields, after.Fields) {
+		op.FieldsChanged = true
+		op.Before.Fields = before.Fields
+		op.After.Fields = after.Fields
+	}
+
+	if len(op.TodoIDs) == 0 && !op.GroupsChanged && !op.WorkflowChanged && !op.FieldsChanged {
+		return nil
+	}
+	return op
+}
+
+// applyOperation turns the from side of an operation into the to side on top
+// of config. Unless force is set it refuses when the store no longer matches
+// from, so a later change is never silently overwritten.
+func applyOperation(config *types.Config, op Operation, from, to types.Config, force bool) error {
+	fromTodos := make(map[string]types.Todo)
+	for _, todo := range from.Todos {
+		fromTodos[todo.ID] = todo
+	}
+	toTodos := make(map[string]types.Todo)
+	for _, todo := range to.Todos {
+		toTodos[todo.ID] = todo
+	}
+
+	if !force {
+		for _, id := range op.TodoIDs {
+			current, err := findTodo(config, id)
+			expected, ok := fromTodos[id]
+			if (err == nil) != ok || (ok && !sameJSON(*current, expected)) {
+				return fmt.Errorf("todo '%s' was changed after '%s', use --force to apply anyway", id, op.Command)
+			}
+		}
+		if op.GroupsChanged && (config.ActiveGroup != from.ActiveGroup || !sameJSON(nonNilGroups(config.Groups), nonNilGroups(from.Groups))) {
+			return fmt.Errorf("groups were changed after '%s', use --force to apply anyway", op.Command)
+		}
+		if op.WorkflowChanged && !sameJSON(config.Workflow, from.Workflow) {
+			return fmt.Errorf("the workflow was changed after '%s', use --force to apply anyway", op.Command)
+		}
+		if op.FieldsChanged && !sameJSON(config.Fields, from.Fields) {
+			return fmt.Errorf("custom fields were changed after '%s', use --force to apply anyway", op.Command)
+		}
+	}
+
+	for _, id := range op.TodoIDs {
+		if todo, ok := toTodos[id]; ok {
+			putTodo(config, todo)
+		} else {
+			deleteTodo(config, id)
+		}
+	}
+
+	if op.GroupsChanged {
+		config.Groups = nonNilGroups(to.Groups)
+		config.ActiveGroup = to.ActiveGroup
+	}
+	if op.WorkflowChanged {
+		config.Workflow = to.Workflow
+	}
+	if op.FieldsChanged {
+		config.Fields = to.Fields
+	}
+
+	return nil
+}
+
+// Undo reverts the last n recorded operations, newest first.
+func (h *Handle) Undo(n int, force bool) ([]Operation, error) {
+	return h.replay(n, force, true)
+}
+
+// Redo reapplies the last n undone operations.
+func (h *Handle) Redo(n int, force bool) ([]Operation, error) {
+	return h.replay(n, force, false)
+}
+
+func (h *Handle) replay(n int, force, undo bool) ([]Operation, error) {
+	hist, err := readHistory(h.path)
+	if err != nil {
+		return nil, err
+	}
+
+	stack := hist.undone
+	action := historyRedo
+	if undo {
+		stack = hist.done
+		action = historyUndo
+	}
+
+	if len(stack) == 0 {
+		return nil, fmt.Errorf("nothing to %s", action)
+	}
+	if n > len(stack) {
+		n = len(stack)
+	}
+
+	config, err := h.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := h.LoadArchive()
+	if err != nil {
+		return nil, err
+	}
+	archiveChanged := false
+
+	var applied []Operation
+	var entries []historyEntry
+	for i := 0; i < n; i++ {
+		op := stack[len(stack)-1-i]
+		if op.SchemaVersion != SchemaVersion {
+			return nil, fmt.Errorf("cannot %s '%s', it was recorded before the store was migrated", action, op.Command)
+		}
+
+		from, to := op.After, op.Before
+		if !undo {
+			from, to = op.Before, op.After
+		}
+
+		if err := applyOperation(config, op, from, to, force); err != nil {
+			return nil, err
+		}
+
+		added, removed := op.Archived, op.Unarchived
+		if undo {
+			added, removed = op.Unarchived, op.Archived
+		}
+		for _, entry := range removed {
+			removeArchived(archive, entry.ID)
+		}
+		putArchived(archive, added...)
+		archiveChanged = archiveChanged || len(added) > 0 || len(removed) > 0
+
+		applied = append(applied, op)
+		entries = append(entries, historyEntry{Action: action, Seq: op.Seq})
+	}
+
+	if _, err := h.beforeWrite(); err != nil {
+		return nil, err
+	}
+	if archiveChanged {
+		if err := h.saveArchive(archive); err != nil {
+			return nil, err
+		}
+	}
+	if err := h.write(config); err != nil {
+		return nil, err
+	}
+	if err := appendHistory(h.path, entries...); err != nil {
+		return nil, err
+	}
+
+	return applied, nil
+}
+
+func (h *Handle) record(before, after *types.Config) error {
+	return h.recordOperation(newOperation(before, after))
+}
+
+func (h *Handle) recordOperation(op *Operation) error {
+	if op == nil {
+		return nil
+	}
+
+	seq, err := nextHistorySeq(h.path)
+	if err != nil {
+		return err
+	}
+	op.Seq = seq
+
+	return appendHistory(h.path, historyEntry{Action: historyDo, Op: op})
+}
+
+// nextHistorySeq returns the sequence number for a new operation. Operations
+// are appended in sequence order, compaction included, so the history is
+// read backwards only as far as its last recorded operation.
+func nextHistorySeq(configPath string) (int, error) {
+	file, err := os.Open(historyPath(configPath))
+	if os.IsNotExist(err) {
+		return 1, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	const chunkSize = 64 << 10
+	end := info.Size()
+	var tail []byte
+	for {
+		i := bytes.LastIndexByte(tail, '\n')
+		if i < 0 && end > 0 {
+			n := min(end, chunkSize)
+			chunk := make([]byte, n, n+int64(len(tail)))
+			if _, err := file.ReadAt(chunk, end-n); err != nil {
+				return 0, err
+			}
+			tail = append(chunk, tail...)
+			end -= n
+			continue
+		}
+
+		line := tail[i+1:]
+		tail = tail[:max(i, 0)]
+		if len(bytes.TrimSpace(line)) > 0 {
+			var entry historyEntry
+			line, err := decodeBlob(configPath, line)
+			if err == nil {
+				err = json.Unmarshal(line, &entry)
+			}
+			if errors.Is(err, ErrWrongPassphrase) || errors.Is(err, ErrNoPassphrase) {
+				return 0, err
+			}
+			// A line that does not decode is a write torn by a crash.
+			if err == nil && entry.Action == historyDo && entry.Op != nil {
+				return entry.Op.Seq + 1, nil
+			}
+		}
+		if i < 0 {
+			return 1, nil
+		}
+	}
+}
+
+func sameJSON(a, b any) bool {
+	aData, aErr := json.Marshal(a)
+	bData, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && bytes.Equal(aData, bData)
+}
+
+func nonNilGroups(groups []types.Group) []types.Group {
+	if groups == nil {
+		return []types.Group{}
+	}
+	return groups
+}
@@ -0,0 +1,169 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/dorukozerr/todo-cli/internal/types"
+)
+
+// openTestStore points the store and the per-user files at a fresh
+// directory and opens it.
+func openTestStore(t *testing.T) *Handle {
+	t.Helper()
+
+	dir := t.TempDir()
+	t.Setenv(EnvHome, dir)
+	t.Setenv(EnvFile, filepath.Join(dir, "todos.json"))
+
+	h, err := Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { h.Close() })
+	return h
+}
+
+func putTestTodo(t *testing.T, h *Handle, number int, task string) {
+	t.Helper()
+
+	todo := types.Todo{ID: task + "-0000-0000", Number: number, Task: task, Urgency: 1, Status: "todo"}
+	if err := h.PutTodo(todo); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func appendRaw(t *testing.T, path, data string) {
+	t.Helper()
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func tasks(config *types.Config) []string {
+	var tasks []string
+	for _, todo := range config.Todos {
+		tasks = append(tasks, todo.Task)
+	}
+	return tasks
+}
+
+func TestAppendHistoryAfterTornLine(t *testing.T) {
+	h := openTestStore(t)
+
+	putTestTodo(t, h, 1, "a")
+	putTestTodo(t, h, 2, "b")
+	appendRaw(t, historyPath(h.path), `{"act`)
+	putTestTodo(t, h, 3, "c")
+	putTestTodo(t, h, 4, "d")
+
+	hist, err := readHistory(h.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var seqs []int
+	for _, op := range hist.done {
+		seqs = append(seqs, op.Seq)
+	}
+	if want := []int{1, 2, 3, 4}; !slices.Equal(seqs, want) {
+		t.Errorf("history seqs = %v, want %v", seqs, want)
+	}
+
+	if _, err := h.Undo(2, false); err != nil {
+		t.Fatal(err)
+	}
+	config, err := h.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tasks(config), []string{"a", "b"}; !slices.Equal(got, want) {
+		t.Errorf("todos after undo = %v, want %v", got, want)
+	}
+
+	bad, err := badHistoryLines(h.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bad) > 0 {
+		t.Errorf("history has unreadable lines %v", bad)
+	}
+}
+
+func TestDoctorRepairsCorruptHistory(t *testing.T) {
+	h := openTestStore(t)
+
+	putTestTodo(t, h, 1, "a")
+	appendRaw(t, historyPath(h.path), "{\"action\": garbage\n")
+	putTestTodo(t, h, 2, "b")
+
+	if _, err := h.Undo(1, false); err == nil {
+		t.Fatal("undo over a corrupt history succeeded")
+	}
+
+	problems, _, err := h.Doctor(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 1 || problems[0].Code != ProblemCorruptHistory {
+		t.Fatalf("problems = %+v, want one %s", problems, ProblemCorruptHistory)
+	}
+
+	if _, _, err := h.Doctor(true); err != nil {
+		t.Fatal(err)
+	}
+	problems, _, err = h.Doctor(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) > 0 {
+		t.Errorf("problems after repair = %+v", problems)
+	}
+
+	if _, err := h.Undo(1, false); err != nil {
+		t.Fatal(err)
+	}
+	config, err := h.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tasks(config), []string{"a"}; !slices.Equal(got, want) {
+		t.Errorf("todos after undo = %v, want %v", got, want)
+	}
+}
+
+func TestDropTornTail(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{"", ""},
+		{"one\ntwo\n", "one\ntwo\n"},
+		{"one\ntw", "one\n"},
+		{"partial", ""},
+	}
+
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "history")
+		if err := os.WriteFile(path, []byte(tt.data), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := dropTornTail(path); err != nil {
+			t.Fatalf("dropTornTail(%q): %v", tt.data, err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != tt.want {
+			t.Errorf("dropTornTail(%q) left %q, want %q", tt.data, data, tt.want)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package fs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/dorukozerr/todo-cli/internal/types"
+)
+
+const (
+	journalOpPut    = "put"
+	journalOpDelete = "delete"
+
+	journalCompactSize = 1 << 20
+)
+
+type journalEntry struct {
+	Op   string      `json:"op"`
+	ID   string      `json:"id,omitempty"`
+	Todo *types.Todo `json:"todo,omitempty"`
+}
+
+// journalStore keeps the last compacted document in the config file and
+// appends single-todo changes to a journal beside it. The journal is folded
+// back into the document on Save or once it grows past journalCompactSize.
+type journalStore struct {
+	path string
+}
+
+func journalPath(configPath string) string {
+	return configPath + ".journal"
+}
+
+func (s *journalStore) Load() (*types.Config, error) {
+	return loadConfig(s.path, true)
+}
+
+func (s *journalStore) Save(config *types.Config) error {
+	err := writeConfigFile(s.path, config)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(journalPath(s.path))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (s *journalStore) GetTodo(id string) (*types.Todo, error) {
+	config, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+	return findTodo(config, id)
+}
+
+func (s *journalStore) PutTodo(todo types.Todo) error {
+	return s.append(journalEntry{Op: journalOpPut, Todo: &todo})
+}
+
+func (s *journalStore) DeleteTodo(id string) error {
+	return s.append(journalEntry{Op: journalOpDelete, ID: id})
+}
+
+func (s *journalStore) Close() error {
+	return nil
+}
+
+func (s *journalStore) append(entry journalEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	data, err = encodeBlob(s.path, data)
+	if err != nil {
+		return err
+	}
+
+	torn, err := s.hasTornTail()
+	if err != nil {
+		return err
+	}
+	if torn {
+		if err := s.compact(); err != nil {
+			return err
+		}
+	}
+
+	journal, err := os.OpenFile(journalPath(s.path), os.O_CREATE|os.O_WRONLY|os.O_APPEND, storeFileMode)
+	if err != nil {
+		return err
+	}
+
+	_, err = journal.Write(append(data, '\n'))
+	if err == nil {
+		err = journal.Sync()
+	}
+	if closeErr := journal.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(journalPath(s.path))
+	if err != nil {
+		return err
+	}
+	if info.Size() >= journalCompactSize {
+		return s.compact()
+	}
+	return nil
+}
+
+func (s *journalStore) hasTornTail() (bool, error) {
+	journal, err := os.Open(journalPath(s.path))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer journal.Close()
+
+	info, err := journal.Stat()
+	if err != nil || info.Size() == 0 {
+		return false, err
+	}
+
+	last := make([]byte, 1)
+	if _, err := journal.ReadAt(last, info.Size()-1); err != nil {
+		return false, err
+	}
+	return last[0] != '\n', nil
+}
+
+func (s *journalStore) compact() error {
+	config, err := s.Load()
+	if err != nil {
+		return err
+	}
+	return s.Save(config)
+}
+
+// replayJournal applies pending journal entries to a raw document. It works
+// below types.Config so that entries written by an older schema can still be
+// migrated together with the document they belong to.
+func replayJournal(doc map[string]any, configPath string) error {
+	data, err := os.ReadFile(journalPath(configPath))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	todos, _ := doc["todos"].([]any)
+
+	lines := bytes.Split(data, []byte("\n"))
+	for i, line := range lines {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var entry struct {
+			Op   string         `json:"op"`
+			ID   string         `json:"id"`
+			Todo map[string]any `json:"todo"`
+		}
+		line, err := decodeBlob(configPath, line)
+		if err == nil {
+			err = json.Unmarshal(line, &entry)
+		}
+		if errors.Is(err, ErrWrongPassphrase) || errors.Is(err, ErrNoPassphrase) {
+			return err
+		}
+		if err != nil {
+			// An unterminated last line is a write torn by a crash; drop it.
+			if i == len(lines)-1 {
+				break
+			}
+			return fmt.Errorf("journal %s line %d: %w", journalPath(configPath), i+1, err)
+		}
+
+		switch entry.Op {
+		case journalOpPut:
+			if entry.Todo == nil {
+				return fmt.Errorf("journal %s line %d: put without todo", journalPath(configPath), i+1)
+			}
+			replaced := false
+			for j, t := range todos {
+				if todo, ok := t.(map[string]any); ok && todo["id"] == entry.Todo["id"] {
+					todos[j] = entry.Todo
+					replaced = true
+					break
+				}
+			}
+			if !replaced {
+				todos = append(todos, entry.Todo)
+			}
+			// Keep the counter past every number handed out, even if the
+			// todo is deleted later in the journal.
+			if number, ok := entry.Todo["number"].(float64); ok {
+				if next, _ := doc["next_number"].(float64); number+1 > next {
+					doc["next_number"] = number + 1
+				}
+			}
+		case journalOpDelete:
+			kept := make([]any, 0, len(todos))
+			for _, t := range todos {
+				if todo, ok := t.(map[string]any); ok && todo["id"] == entry.ID {
+					continue
+				}
+				kept = append(kept, t)
+			}
+			todos = kept
+		default:
+			return fmt.Errorf("journal %s line %d: unknown op %q", journalPath(configPath), i+1, entry.Op)
+		}
+	}
+
+	doc["todos"] = todos
+	return nil
+}
@@ -0,0 +1,88 @@
+package fs
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/dorukozerr/todo-cli/internal/types"
+)
+
+func TestReplayJournal(t *testing.T) {
+	s := &journalStore{path: filepath.Join(t.TempDir(), "todos.json")}
+	if _, err := s.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	todo := func(number int, task string) types.Todo {
+		return types.Todo{ID: task + "-0000-0000", Number: number, Task: task, Urgency: 1, Status: "todo"}
+	}
+	updated := todo(1, "a")
+	updated.Notes = "updated"
+
+	for _, err := range []error{
+		s.PutTodo(todo(1, "a")),
+		s.PutTodo(todo(2, "b")),
+		s.PutTodo(todo(3, "c")),
+		s.PutTodo(updated),
+		s.DeleteTodo("c-0000-0000"),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tasks(config), []string{"a", "b"}; !slices.Equal(got, want) {
+		t.Errorf("todos = %v, want %v", got, want)
+	}
+	if config.Todos[0].Notes != "updated" {
+		t.Errorf("todo a has notes %q, want the replaced todo", config.Todos[0].Notes)
+	}
+	// Number 3 was handed out before c was deleted, so it stays used.
+	if config.NextNumber != 4 {
+		t.Errorf("next number = %d, want 4", config.NextNumber)
+	}
+
+	appendRaw(t, journalPath(s.path), `{"op":"put","todo":{"id":`)
+	config, err = s.Load()
+	if err != nil {
+		t.Fatalf("loading with a torn last line: %v", err)
+	}
+	if got, want := tasks(config), []string{"a", "b"}; !slices.Equal(got, want) {
+		t.Errorf("todos with a torn last line = %v, want %v", got, want)
+	}
+
+	if err := s.PutTodo(todo(4, "d")); err != nil {
+		t.Fatal(err)
+	}
+	config, err = s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tasks(config), []string{"a", "b", "d"}; !slices.Equal(got, want) {
+		t.Errorf("todos after appending past a torn line = %v, want %v", got, want)
+	}
+}
+
+func TestReplayJournalCorruptLine(t *testing.T) {
+	s := &journalStore{path: filepath.Join(t.TempDir(), "todos.json")}
+	if _, err := s.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.PutTodo(types.Todo{ID: "a-0000-0000", Number: 1, Task: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	appendRaw(t, journalPath(s.path), "not json\n")
+	if err := s.PutTodo(types.Todo{ID: "b-0000-0000", Number: 2, Task: "b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Load(); err == nil {
+		t.Error("loading a journal with a corrupt line in the middle succeeded")
+	}
+}
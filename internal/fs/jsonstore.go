@@ -0,0 +1,45 @@
+package fs
+
+import "github.com/dorukozerr/todo-cli/internal/types"
+
+type jsonStore struct {
+	path string
+}
+
+func (s *jsonStore) Load() (*types.Config, error) {
+	return loadConfig(s.path, false)
+}
+
+func (s *jsonStore) Save(config *types.Config) error {
+	return writeConfigFile(s.path, config)
+}
+
+func (s *jsonStore) GetTodo(id string) (*types.Todo, error) {
+	config, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+	return findTodo(config, id)
+}
+
+func (s *jsonStore) PutTodo(todo types.Todo) error {
+	config, err := s.Load()
+	if err != nil {
+		return err
+	}
+	putTodo(config, todo)
+	return s.Save(config)
+}
+
+func (s *jsonStore) DeleteTodo(id string) error {
+	config, err := s.Load()
+	if err != nil {
+		return err
+	}
+	deleteTodo(config, id)
+	return s.Save(config)
+}
+
+func (s *jsonStore) Close() error {
+	return nil
+}
@@ -0,0 +1,553 @@
+package fs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dorukozerr/todo-cli/internal/utils"
+)
+
+// SchemaVersion is the config.json layout this binary reads and writes.
+const SchemaVersion = 7
+
+var legacyMonthPattern = regexp.MustCompile(`^\d+m$`)
+
+type migration struct {
+	version     int
+	description string
+	apply       func(doc map[string]any, ctx migrationContext) ([]string, error)
+}
+
+// migrationContext tells a migration which store it is upgrading, and
+// whether it may touch anything outside the document.
+type migrationContext struct {
+	configPath string
+	dryRun     bool
+	// storeIDs maps the numbers of the store's todos to their IDs, so an
+	// archive can keep references that point into the store.
+	storeIDs map[string]string
+}
+
+// migrations upgrade a raw document one schema version at a time. Entry i
+// takes a document from version i to version i+1, so new migrations are only
+// ever appended.
+var migrations = []migration{
+	{
+		version:     1,
+		description: "store the default group as an empty group name",
+		apply:       migrateDefaultGroupName,
+	},
+	{
+		version:     2,
+		description: "move the active group out of the store into per-user state",
+		apply:       migrateActiveGroupToState,
+	},
+	{
+		version:     3,
+		description: "record when each todo was created, updated and completed",
+		apply:       migrateTodoTimestamps,
+	},
+	{
+		version:     4,
+		description: "give every todo a workflow status from its completed flag",
+		apply:       migrateTodoStatus,
+	},
+	{
+		version:     5,
+		description: "give every todo and group a permanent UUID, keeping old todo IDs as numbers",
+		apply:       migrateTodoIDs,
+	},
+	{
+		version:     6,
+		description: "move custom field declarations from user settings into the store",
+		apply:       migrateFieldDefs,
+	},
+	{
+		version:     7,
+		description: "write month repeat intervals as 'mo', since 'm' means minutes in due dates",
+		apply:       migrateMonthRecurrence,
+	},
+}
+
+type MigrationStep struct {
+	Version     int
+	Description string
+	Changes     []string
+}
+
+type MigrationReport struct {
+	Path   string
+	From   int
+	To     int
+	Backup string
+	Steps  []MigrationStep
+}
+
+// Migrate upgrades the store to SchemaVersion. With dryRun set it only
+// reports what would change.
+func Migrate(dryRun bool) (*MigrationReport, error) {
+	configPath, err := StorePath()
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := lock(configPath)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	doc, err := readDocument(configPath, true)
+	if os.IsNotExist(err) {
+		return &MigrationReport{Path: configPath, From: SchemaVersion, To: SchemaVersion}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := migrateDocument(doc, migrationContext{configPath: configPath, dryRun: dryRun})
+	if err != nil {
+		return nil, err
+	}
+	report.Path = configPath
+
+	if dryRun || len(report.Steps) == 0 {
+		return report, nil
+	}
+
+	report.Backup, err = writeMigratedDocument(configPath, doc, report.From)
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func documentVersion(doc map[string]any) int {
+	version, _ := doc["schema_version"].(float64)
+	return int(version)
+}
+
+func migrateDocument(doc map[string]any, ctx migrationContext) (*MigrationReport, error) {
+	from := documentVersion(doc)
+	if from > SchemaVersion {
+		return nil, fmt.Errorf("store has schema version %d but this todo binary only supports up to %d, please upgrade it", from, SchemaVersion)
+	}
+
+	report := &MigrationReport{From: from, To: SchemaVersion}
+	for _, m := range migrations[from:] {
+		changes, err := m.apply(doc, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("migration to schema version %d: %w", m.version, err)
+		}
+		report.Steps = append(report.Steps, MigrationStep{
+			Version:     m.version,
+			Description: m.description,
+			Changes:     changes,
+		})
+		doc["schema_version"] = m.version
+	}
+
+	return report, nil
+}
+
+// writeMigratedDocument copies the original store aside before replacing it
+// with the migrated document. A pending journal is folded into the new
+// document, so it is backed up and removed as well.
+func writeMigratedDocument(configPath string, doc map[string]any, from int) (string, error) {
+	stamp := time.Now().Format("20060102T150405")
+	backupPath := fmt.Sprintf("%s.v%d-%s.bak", configPath, from, stamp)
+
+	for _, path := range []string{configPath, journalPath(configPath)} {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+
+		target := backupPath
+		if path != configPath {
+			target = fmt.Sprintf("%s.v%d-%s.bak", path, from, stamp)
+		}
+		if err := writeFileAtomic(target, data, storeFileMode); err != nil {
+			return "", err
+		}
+	}
+
+	config, err := decodeDocument(doc)
+	if err != nil {
+		return "", err
+	}
+	if err := writeConfigFile(configPath, config); err != nil {
+		return "", err
+	}
+
+	err = os.Remove(journalPath(configPath))
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	return backupPath, nil
+}
+
+// migrationBackups lists the copies writeMigratedDocument left beside the
+// store and its journal.
+func migrationBackups(configPath string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Dir(configPath))
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".bak") {
+			continue
+		}
+		for _, prefix := range []string{configPath, journalPath(configPath)} {
+			if strings.HasPrefix(name, filepath.Base(prefix)+".v") {
+				paths = append(paths, filepath.Join(filepath.Dir(configPath), name))
+				break
+			}
+		}
+	}
+	return paths, nil
+}
+
+func migrateDefaultGroupName(doc map[string]any, ctx migrationContext) ([]string, error) {
+	var changes []string
+
+	if doc["active_group"] == "default" {
+		doc["active_group"] = ""
+		changes = append(changes, `active_group: "default" -> ""`)
+	}
+
+	todos, _ := doc["todos"].([]any)
+	for _, t := range todos {
+		todo, ok := t.(map[string]any)
+		if !ok {
+			continue
+		}
+		if todo["group"] == "default" {
+			todo["group"] = ""
+			changes = append(changes, fmt.Sprintf(`todo %v: group "default" -> ""`, todo["id"]))
+		}
+	}
+
+	return changes, nil
+}
+
+func migrateActiveGroupToState(doc map[string]any, ctx migrationContext) ([]string, error) {
+	value, ok := doc["active_group"]
+	if !ok {
+		return nil, nil
+	}
+	group, _ := value.(string)
+
+	if !ctx.dryRun {
+		if ctx.configPath != "" {
+			if err := setActiveGroup(ctx.configPath, group); err != nil {
+				return nil, err
+			}
+		}
+		delete(doc, "active_group")
+	}
+
+	if group == "" {
+		return nil, nil
+	}
+	return []string{fmt.Sprintf("active_group %q -> per-user state", group)}, nil
+}
+
+// migrateTodoTimestamps backfills created_at, updated_at and completed_at.
+// The real times are unknown, so the store's last modification is used, or
+// the archive time for archived todos.
+func migrateTodoTimestamps(doc map[string]any, ctx migrationContext) ([]string, error) {
+	fallback := time.Now()
+	if ctx.configPath != "" {
+		if info, err := os.Stat(ctx.configPath); err == nil {
+			fallback = info.ModTime()
+		}
+	}
+
+	backfilled := 0
+	todos, _ := doc["todos"].([]any)
+	for _, t := range todos {
+		todo, ok := t.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		stamp := fallback.UTC().Format(time.RFC3339Nano)
+		if archivedAt, ok := todo["archived_at"].(string); ok {
+			stamp = archivedAt
+		}
+
+		changed := false
+		for _, field := range []string{"created_at", "updated_at"} {
+			if _, ok := todo[field]; !ok {
+				todo[field] = stamp
+				changed = true
+			}
+		}
+		if completed, _ := todo["completed"].(bool); completed {
+			if _, ok := todo["completed_at"]; !ok {
+				todo["completed_at"] = stamp
+				changed = true
+			}
+		}
+		if changed {
+			backfilled++
+		}
+	}
+
+	if backfilled == 0 {
+		return nil, nil
+	}
+	return []string{fmt.Sprintf("%d todo(s): missing timestamps set to %s", backfilled, fallback.Format("2006-01-02 15:04"))}, nil
+}
+
+// migrateTodoStatus gives every todo a status of the default workflow based
+// on its completed flag, which is kept as a mirror of the status.
+func migrateTodoStatus(doc map[string]any, ctx migrationContext) ([]string, error) {
+	counts := make(map[string]int)
+	todos, _ := doc["todos"].([]any)
+	for _, t := range todos {
+		todo, ok := t.(map[string]any)
+		if !ok {
+			continue
+		}
+		if _, ok := todo["status"]; ok {
+			continue
+		}
+
+		status := utils.StatusTodo
+		if completed, _ := todo["completed"].(bool); completed {
+			status = utils.StatusDone
+		}
+		todo["status"] = status
+		counts[status]++
+	}
+
+	var changes []string
+	for _, status := range []string{utils.StatusTodo, utils.StatusDone} {
+		if counts[status] > 0 {
+			changes = append(changes, fmt.Sprintf("%d todo(s): status set to %s", counts[status], status))
+		}
+	}
+	return changes, nil
+}
+
+// migrateTodoIDs replaces the numeric todo IDs with random UUIDs. The old ID
+// stays as the todo's number unless it was not a free positive number, and
+// parent and dependency references are rewritten to the new IDs. References
+// to todos that no longer exist are dropped.
+func migrateTodoIDs(doc map[string]any, ctx migrationContext) ([]string, error) {
+	var todos []map[string]any
+	items, _ := doc["todos"].([]any)
+	for _, t := range items {
+		if todo, ok := t.(map[string]any); ok {
+			todos = append(todos, todo)
+		}
+	}
+
+	next, err := archivedNumberLimit(ctx.configPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, todo := range todos {
+		if n, err := strconv.Atoi(fmt.Sprint(todo["id"])); err == nil {
+			next = max(next, n+1)
+		}
+	}
+
+	ids := make(map[string]string)
+	taken := make(map[int]bool)
+	renumbered := 0
+	for _, todo := range todos {
+		old := fmt.Sprint(todo["id"])
+		n, err := strconv.Atoi(old)
+		if err != nil || n < 1 || taken[n] {
+			n = next
+			next++
+			renumbered++
+		}
+		taken[n] = true
+
+		id := utils.GenerateUUID()
+		if _, ok := ids[old]; !ok {
+			ids[old] = id
+		}
+		todo["id"] = id
+		todo["number"] = n
+	}
+	doc["next_number"] = next
+
+	resolve := func(ref any) (string, bool) {
+		key := fmt.Sprint(ref)
+		if id, ok := ids[key]; ok {
+			return id, true
+		}
+		id, ok := ctx.storeIDs[key]
+		return id, ok
+	}
+	dropped := 0
+	for _, todo := range todos {
+		if parent, ok := todo["parent"]; ok && parent != "" {
+			if id, ok := resolve(parent); ok {
+				todo["parent"] = id
+			} else {
+				delete(todo, "parent")
+				dropped++
+			}
+		}
+		if deps, ok := todo["depends_on"].([]any); ok {
+			kept := make([]any, 0, len(deps))
+			for _, dep := range deps {
+				if id, ok := resolve(dep); ok {
+					kept = append(kept, id)
+				} else {
+					dropped++
+				}
+			}
+			if len(kept) == 0 {
+				delete(todo, "depends_on")
+			} else {
+				todo["depends_on"] = kept
+			}
+		}
+	}
+
+	groupCount := 0
+	groups, _ := doc["groups"].([]any)
+	for _, g := range groups {
+		group, ok := g.(map[string]any)
+		if !ok {
+			continue
+		}
+		if id, _ := group["id"].(string); id == "" {
+			group["id"] = utils.GenerateUUID()
+			groupCount++
+		}
+	}
+
+	var changes []string
+	if len(todos) > 0 {
+		changes = append(changes, fmt.Sprintf("%d todo(s): new UUIDs, old IDs kept as numbers", len(todos)))
+	}
+	if renumbered > 0 {
+		changes = append(changes, fmt.Sprintf("%d todo(s): ID was not a free number, numbered from %d", renumbered, next-renumbered))
+	}
+	if groupCount > 0 {
+		changes = append(changes, fmt.Sprintf("%d group(s): new UUIDs", groupCount))
+	}
+	if dropped > 0 {
+		changes = append(changes, fmt.Sprintf("%d reference(s) to todos that no longer exist dropped", dropped))
+	}
+	return changes, nil
+}
+
+// migrateFieldDefs copies the custom fields declared in settings.json into
+// the store, so everyone sharing it sees the same fields. settings.json is
+// left alone as other stores may still need to pick them up.
+func migrateFieldDefs(doc map[string]any, ctx migrationContext) ([]string, error) {
+	if ctx.configPath == "" {
+		return nil, nil
+	}
+	if _, ok := doc["fields"]; ok {
+		return nil, nil
+	}
+
+	path, err := SettingsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var settings struct {
+		Fields []any `json:"fields"`
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(settings.Fields) == 0 {
+		return nil, nil
+	}
+
+	doc["fields"] = settings.Fields
+	return []string{fmt.Sprintf("%d custom field(s) copied from %s", len(settings.Fields), path)}, nil
+}
+
+// migrateMonthRecurrence rewrites repeat rules such as "6m" to "6mo", which
+// is how month intervals are written now that a bare "m" is refused.
+func migrateMonthRecurrence(doc map[string]any, ctx migrationContext) ([]string, error) {
+	rewritten := 0
+	todos, _ := doc["todos"].([]any)
+	for _, t := range todos {
+		todo, ok := t.(map[string]any)
+		if !ok {
+			continue
+		}
+		if recur, _ := todo["recur"].(string); legacyMonthPattern.MatchString(recur) {
+			todo["recur"] = recur + "o"
+			rewritten++
+		}
+	}
+
+	if rewritten == 0 {
+		return nil, nil
+	}
+	return []string{fmt.Sprintf("%d todo(s): month repeat interval written with 'mo'", rewritten)}, nil
+}
+
+// archivedNumberLimit is one past the highest old numeric ID in the archive
+// beside the store, so todos added after the migration do not share a number
+// with an archived one.
+func archivedNumberLimit(configPath string) (int, error) {
+	if configPath == "" {
+		return 1, nil
+	}
+
+	data, err := os.ReadFile(archivePath(configPath))
+	if os.IsNotExist(err) {
+		return 1, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	data, err = decodeBlob(configPath, data)
+	if err != nil {
+		return 0, err
+	}
+
+	var archive struct {
+		Todos []struct {
+			ID     any `json:"id"`
+			Number int `json:"number"`
+		} `json:"todos"`
+	}
+	if err := json.Unmarshal(data, &archive); err != nil {
+		return 0, err
+	}
+
+	limit := 1
+	for _, todo := range archive.Todos {
+		n, _ := strconv.Atoi(fmt.Sprint(todo.ID))
+		limit = max(limit, n+1, todo.Number+1)
+	}
+	return limit, nil
+}
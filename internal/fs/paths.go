@@ -0,0 +1,123 @@
+package fs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	EnvFile = "TODO_CLI_FILE"
+	EnvHome = "TODO_CLI_HOME"
+
+	ProjectFileName = ".todo.json"
+)
+
+const (
+	SourceFlag    = "--file"
+	SourceEnv     = "$" + EnvFile
+	SourceProject = "project"
+	SourceGlobal  = "global"
+)
+
+type StoreLocation struct {
+	Path   string
+	Source string
+}
+
+var storeFileOverride string
+
+// SetStoreFile points every command at the given store file. It is wired to
+// the global --file flag and wins over the environment.
+func SetStoreFile(path string) {
+	storeFileOverride = path
+}
+
+// ConfigHome is the directory holding per-user files such as settings. It is
+// $TODO_CLI_HOME, $XDG_CONFIG_HOME/todo-cli or ~/.config/todo-cli.
+func ConfigHome() (string, error) {
+	if home := os.Getenv(EnvHome); home != "" {
+		return filepath.Abs(home)
+	}
+
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" && filepath.IsAbs(xdg) {
+		return filepath.Join(xdg, "todo-cli"), nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(homeDir, ".config", "todo-cli"), nil
+}
+
+// LocateStore resolves the todo store in order of precedence: the --file
+// flag, $TODO_CLI_FILE, a .todo.json found by walking up from the working
+// directory, then config.json inside ConfigHome.
+func LocateStore() (StoreLocation, error) {
+	if storeFileOverride != "" {
+		path, err := filepath.Abs(storeFileOverride)
+		return StoreLocation{Path: path, Source: SourceFlag}, err
+	}
+
+	if file := os.Getenv(EnvFile); file != "" {
+		path, err := filepath.Abs(file)
+		return StoreLocation{Path: path, Source: SourceEnv}, err
+	}
+
+	workDir, err := os.Getwd()
+	if err == nil {
+		if path, ok := FindProjectStore(workDir); ok {
+			return StoreLocation{Path: path, Source: SourceProject}, nil
+		}
+	}
+
+	configHome, err := ConfigHome()
+	if err != nil {
+		return StoreLocation{}, err
+	}
+
+	return StoreLocation{Path: filepath.Join(configHome, "config.json"), Source: SourceGlobal}, nil
+}
+
+func StorePath() (string, error) {
+	location, err := LocateStore()
+	return location.Path, err
+}
+
+// FindProjectStore walks up from dir looking for a .todo.json, the way git
+// looks for .git.
+func FindProjectStore(dir string) (string, bool) {
+	for {
+		path := filepath.Join(dir, ProjectFileName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, true
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// InitProjectStore creates an empty .todo.json in dir.
+func InitProjectStore(dir string) (string, error) {
+	path := filepath.Join(dir, ProjectFileName)
+
+	unlock, err := lock(path)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf("%s already exists", path)
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+
+	return path, writeConfigFile(path, newConfig())
+}
@@ -0,0 +1,263 @@
+package fs
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dorukozerr/todo-cli/internal/types"
+)
+
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+
+	DateFormatISO = "iso"
+	DateFormatUS  = "us"
+	DateFormatEU  = "eu"
+)
+
+// Setting describes one typed key of settings.json.
+type Setting struct {
+	Key         string
+	Type        string
+	Description string
+	get         func(s *types.Settings) string
+	set         func(s *types.Settings, value string) error
+}
+
+func (s Setting) Get(settings *types.Settings) string {
+	return s.get(settings)
+}
+
+var settingsRegistry = []Setting{
+	enumSetting("backend", "Storage backend for todo stores", []string{BackendJSON, BackendJournal},
+		func(s *types.Settings) *string { return &s.Backend }),
+	intSetting("backup_keep", "Number of recent automatic snapshots to keep", 0, 10000,
+		func(s *types.Settings) *int { return &s.BackupKeep }),
+	intSetting("backup_days", "Days for which one automatic snapshot per day is kept", 0, 3650,
+		func(s *types.Settings) *int { return &s.BackupDays }),
+	intSetting("default_urgency", "Urgency used by 'todo add' when --urgency is not given", 1, 5,
+		func(s *types.Settings) *int { return &s.DefaultUrgency }),
+	enumSetting("color", "When to use colored output", []string{ColorAuto, ColorAlways, ColorNever},
+		func(s *types.Settings) *string { return &s.Color }),
+	enumSetting("date_format", "How dates are printed", []string{DateFormatISO, DateFormatUS, DateFormatEU},
+		func(s *types.Settings) *string { return &s.DateFormat }),
+	intSetting("archive_after_days", "Archive todos completed this many days ago automatically, 0 turns it off", 0, 3650,
+		func(s *types.Settings) *int { return &s.ArchiveAfterDays }),
+	stringSetting("opener", "Command 'todo open' runs on a link, {} is replaced by the link; empty uses the system default",
+		func(s *types.Settings) *string { return &s.Opener }),
+	enumSetting("hyperlinks", "When 'todo list' prints links as clickable terminal hyperlinks", []string{ColorAuto, ColorAlways, ColorNever},
+		func(s *types.Settings) *string { return &s.Hyperlinks }),
+	floatSetting("score_urgency", "Score per urgency level, used by 'todo list --sort score'", -100, 100,
+		func(s *types.Settings) *float64 { return &s.ScoreUrgency }),
+	floatSetting("score_age", "Score a todo gains by growing old, reached after score_age_days", -100, 100,
+		func(s *types.Settings) *float64 { return &s.ScoreAge }),
+	intSetting("score_age_days", "Days after which a todo's age adds the full score_age", 1, 3650,
+		func(s *types.Settings) *int { return &s.ScoreAgeDays }),
+	floatSetting("score_due", "Score of a todo a week overdue; a fifth of it two weeks before the due date", -100, 100,
+		func(s *types.Settings) *float64 { return &s.ScoreDue }),
+	floatSetting("score_blocking", "Score of a todo other open todos depend on", -100, 100,
+		func(s *types.Settings) *float64 { return &s.ScoreBlocking }),
+	floatSetting("score_blocked", "Score of a todo that depends on open todos", -100, 100,
+		func(s *types.Settings) *float64 { return &s.ScoreBlocked }),
+	floatSetting("score_tags", "Score of a todo with three or more tags, less with fewer", -100, 100,
+		func(s *types.Settings) *float64 { return &s.ScoreTags }),
+}
+
+func DefaultSettings() *types.Settings {
+	return &types.Settings{
+		Backend:        BackendJSON,
+		BackupKeep:     20,
+		BackupDays:     30,
+		DefaultUrgency: 1,
+		Color:          ColorAuto,
+		DateFormat:     DateFormatISO,
+		Hyperlinks:     ColorAuto,
+		ScoreUrgency:   2,
+		ScoreAge:       5,
+		ScoreAgeDays:   90,
+		ScoreDue:       12,
+		ScoreBlocking:  8,
+		ScoreBlocked:   -5,
+		ScoreTags:      1,
+	}
+}
+
+func Settings() []Setting {
+	return settingsRegistry
+}
+
+func LookupSetting(key string) (Setting, error) {
+	for _, setting := range settingsRegistry {
+		if setting.Key == key {
+			return setting, nil
+		}
+	}
+	return Setting{}, fmt.Errorf("unknown setting '%s'", key)
+}
+
+func SettingsPath() (string, error) {
+	configHome, err := ConfigHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configHome, "settings.json"), nil
+}
+
+func GetSettings() (*types.Settings, error) {
+	path, err := SettingsPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return DefaultSettings(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := ParseSettings(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return settings, nil
+}
+
+// ParseSettings decodes settings.json on top of the defaults and validates
+// every key.
+func ParseSettings(data []byte) (*types.Settings, error) {
+	settings := DefaultSettings()
+
+	err := json.Unmarshal(data, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, setting := range settingsRegistry {
+		if err := setting.set(settings, setting.get(settings)); err != nil {
+			return nil, err
+		}
+	}
+
+	return settings, nil
+}
+
+func SaveSettings(settings *types.Settings) error {
+	path, err := SettingsPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	unlock, err := lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return writeFileAtomic(path, data, 0644)
+}
+
+// SetSetting validates value against the key's type and saves it.
+func SetSetting(key, value string) error {
+	setting, err := LookupSetting(key)
+	if err != nil {
+		return err
+	}
+
+	settings, err := GetSettings()
+	if err != nil {
+		return err
+	}
+
+	if err := setting.set(settings, value); err != nil {
+		return err
+	}
+
+	return SaveSettings(settings)
+}
+
+func intSetting(key, description string, min, max int, field func(s *types.Settings) *int) Setting {
+	return Setting{
+		Key:         key,
+		Type:        fmt.Sprintf("int %d-%d", min, max),
+		Description: description,
+		get: func(s *types.Settings) string {
+			return strconv.Itoa(*field(s))
+		},
+		set: func(s *types.Settings, value string) error {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < min || n > max {
+				return fmt.Errorf("%s must be a number between %d and %d", key, min, max)
+			}
+			*field(s) = n
+			return nil
+		},
+	}
+}
+
+func floatSetting(key, description string, min, max float64, field func(s *types.Settings) *float64) Setting {
+	return Setting{
+		Key:         key,
+		Type:        fmt.Sprintf("number %g to %g", min, max),
+		Description: description,
+		get: func(s *types.Settings) string {
+			return strconv.FormatFloat(*field(s), 'f', -1, 64)
+		},
+		set: func(s *types.Settings, value string) error {
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil || math.IsNaN(n) || n < min || n > max {
+				return fmt.Errorf("%s must be a number between %g and %g", key, min, max)
+			}
+			*field(s) = n
+			return nil
+		},
+	}
+}
+
+func stringSetting(key, description string, field func(s *types.Settings) *string) Setting {
+	return Setting{
+		Key:         key,
+		Type:        "string",
+		Description: description,
+		get: func(s *types.Settings) string {
+			return *field(s)
+		},
+		set: func(s *types.Settings, value string) error {
+			*field(s) = value
+			return nil
+		},
+	}
+}
+
+func enumSetting(key, description string, values []string, field func(s *types.Settings) *string) Setting {
+	return Setting{
+		Key:         key,
+		Type:        strings.Join(values, "|"),
+		Description: description,
+		get: func(s *types.Settings) string {
+			return *field(s)
+		},
+		set: func(s *types.Settings, value string) error {
+			if !slices.Contains(values, value) {
+				return fmt.Errorf("%s must be one of %s", key, strings.Join(values, ", "))
+			}
+			*field(s) = value
+			return nil
+		},
+	}
+}
@@ -0,0 +1,95 @@
+package fs
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// userState holds per-user state that must not end up in a shared store,
+// such as the active group, keyed by store path.
+type userState struct {
+	ActiveGroups map[string]string `json:"active_groups"`
+}
+
+func statePath() (string, error) {
+	configHome, err := ConfigHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configHome, "state.json"), nil
+}
+
+func readState() (*userState, error) {
+	state := &userState{ActiveGroups: map[string]string{}}
+
+	path, err := statePath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, state)
+	if err != nil {
+		return nil, err
+	}
+	if state.ActiveGroups == nil {
+		state.ActiveGroups = map[string]string{}
+	}
+
+	return state, nil
+}
+
+func activeGroup(configPath string) (string, error) {
+	state, err := readState()
+	if err != nil {
+		return "", err
+	}
+	return state.ActiveGroups[configPath], nil
+}
+
+func setActiveGroup(configPath, group string) error {
+	current, err := activeGroup(configPath)
+	if err != nil || current == group {
+		return err
+	}
+
+	path, err := statePath()
+	if err != nil {
+		return err
+	}
+
+	unlock, err := lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := readState()
+	if err != nil {
+		return err
+	}
+
+	if state.ActiveGroups[configPath] == group {
+		return nil
+	}
+	if group == "" {
+		delete(state.ActiveGroups, configPath)
+	} else {
+		state.ActiveGroups[configPath] = group
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path, data, 0644)
+}
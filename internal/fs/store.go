@@ -0,0 +1,145 @@
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dorukozerr/todo-cli/internal/types"
+)
+
+const (
+	BackendJSON    = "json"
+	BackendJournal = "journal"
+)
+
+// minPrefixLength is the shortest UUID prefix a todo can be picked by.
+const minPrefixLength = 4
+
+var (
+	ErrTodoNotFound = errors.New("todo not found")
+	ErrAmbiguousID  = errors.New("ambiguous todo ID")
+)
+
+// Store is the persistence layer behind every command. Load and Save work on
+// the whole document, the todo methods let a backend persist a single change
+// without rewriting everything.
+type Store interface {
+	Load() (*types.Config, error)
+	Save(config *types.Config) error
+	GetTodo(id string) (*types.Todo, error)
+	PutTodo(todo types.Todo) error
+	DeleteTodo(id string) error
+	Close() error
+}
+
+func newBackend(backend, configPath string) (Store, error) {
+	switch backend {
+	case "", BackendJSON:
+		if err := foldJournal(configPath); err != nil {
+			return nil, err
+		}
+		return &jsonStore{path: configPath}, nil
+	case BackendJournal:
+		return &journalStore{path: configPath}, nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", backend)
+	}
+}
+
+// foldJournal compacts a journal left behind after switching back to the
+// json backend, so its entries are not silently dropped.
+func foldJournal(configPath string) error {
+	_, err := os.Stat(journalPath(configPath))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return (&journalStore{path: configPath}).compact()
+}
+
+func findTodo(config *types.Config, id string) (*types.Todo, error) {
+	for i := range config.Todos {
+		if config.Todos[i].ID == id {
+			return &config.Todos[i], nil
+		}
+	}
+	return nil, ErrTodoNotFound
+}
+
+// ResolveTodo finds the todo a user means by ref: its number, or a prefix of
+// its UUID of at least minPrefixLength characters that no other todo
+// shares. A ref made of digits only is always a number.
+func ResolveTodo(config *types.Config, ref string) (*types.Todo, error) {
+	i, err := resolveRef(config.Todos, ref)
+	if err != nil {
+		return nil, err
+	}
+	return &config.Todos[i], nil
+}
+
+func resolveRef(todos []types.Todo, ref string) (int, error) {
+	ref = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(ref), "#"))
+	if ref == "" {
+		return -1, ErrTodoNotFound
+	}
+
+	// A number that is gone must not fall through to a UUID that happens
+	// to start with the same digits.
+	if n, err := strconv.Atoi(ref); err == nil {
+		for i, todo := range todos {
+			if todo.Number == n {
+				return i, nil
+			}
+		}
+		return -1, ErrTodoNotFound
+	}
+	if len(ref) < minPrefixLength {
+		return -1, ErrTodoNotFound
+	}
+
+	var matches []int
+	for i, todo := range todos {
+		if strings.HasPrefix(todo.ID, ref) {
+			matches = append(matches, i)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return -1, ErrTodoNotFound
+	case 1:
+		return matches[0], nil
+	}
+
+	candidates := make([]string, 0, len(matches))
+	for _, i := range matches {
+		candidates = append(candidates, fmt.Sprintf("[%d] %s (%s)", todos[i].Number, todos[i].ID, todos[i].Task))
+	}
+	return -1, fmt.Errorf("%w '%s', it matches %s", ErrAmbiguousID, ref, strings.Join(candidates, ", "))
+}
+
+func putTodo(config *types.Config, todo types.Todo) {
+	config.NextNumber = max(config.NextNumber, todo.Number+1)
+	for i := range config.Todos {
+		if config.Todos[i].ID == todo.ID {
+			config.Todos[i] = todo
+			return
+		}
+	}
+	config.Todos = append(config.Todos, todo)
+}
+
+func deleteTodo(config *types.Config, id string) {
+	newTodos := make([]types.Todo, 0, len(config.Todos))
+	for _, todo := range config.Todos {
+		if todo.ID != id {
+			newTodos = append(newTodos, todo)
+		}
+	}
+	config.Todos = newTodos
+}
@@ -1,20 +1,111 @@
 package types
 
+import "time"
+
 type Group struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
 }
 
+// Todo is one item in the store. ID is a permanent UUID that references
+// such as Parent and DependsOn use; Number is the short number shown to
+// people. Status is a state of the store's workflow; Completed mirrors
+// whether that state is a closed one. A todo with a Wait in the future is
+// left out of the default listing until then.
 type Todo struct {
-	ID        string `json:"id"`
-	Group     string `json:"group"`
-	Urgency   int    `json:"urgency"`
-	Task      string `json:"task"`
-	Completed bool   `json:"completed"`
+	ID          string            `json:"id"`
+	Number      int               `json:"number"`
+	Group       string            `json:"group"`
+	Urgency     int               `json:"urgency"`
+	Task        string            `json:"task"`
+	Status      string            `json:"status"`
+	Completed   bool              `json:"completed"`
+	Parent      string            `json:"parent,omitempty"`
+	DependsOn   []string          `json:"depends_on,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	Notes       string            `json:"notes,omitempty"`
+	Links       []string          `json:"links,omitempty"`
+	Fields      map[string]string `json:"fields,omitempty"`
+	Due         *time.Time        `json:"due,omitempty"`
+	Wait        *time.Time        `json:"wait,omitempty"`
+	Recur       string            `json:"recur,omitempty"`
+	TimeLog     []TimeEntry       `json:"time_log,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	CompletedAt *time.Time        `json:"completed_at,omitempty"`
+}
+
+// TimeEntry is one interval worked on a todo. End is nil while the timer
+// is running.
+type TimeEntry struct {
+	Start time.Time  `json:"start"`
+	End   *time.Time `json:"end,omitempty"`
+}
+
+// State is one step of a workflow. Todos in a closed state count as done.
+type State struct {
+	Name   string `json:"name"`
+	Closed bool   `json:"closed,omitempty"`
+	Symbol string `json:"symbol,omitempty"`
+}
+
+// Workflow lists the states a todo can be in, in display order. New and
+// reopened todos start in Initial, 'todo complete' moves them to Done.
+// Transitions maps a state to the states it may move to; a state without
+// an entry may move to any state.
+type Workflow struct {
+	States      []State             `json:"states"`
+	Initial     string              `json:"initial"`
+	Done        string              `json:"done"`
+	Transitions map[string][]string `json:"transitions,omitempty"`
 }
 
+// Config is the store document. NextNumber is the number the next todo
+// gets, kept so that numbers of deleted todos are not handed out again.
+// Fields are the custom fields declared for the store's todos.
 type Config struct {
-	Groups      []Group `json:"groups"`
-	ActiveGroup string  `json:"active_group"`
-	Todos       []Todo  `json:"todos"`
+	SchemaVersion int        `json:"schema_version"`
+	NextNumber    int        `json:"next_number"`
+	Groups        []Group    `json:"groups"`
+	ActiveGroup   string     `json:"active_group,omitempty"`
+	Workflow      *Workflow  `json:"workflow,omitempty"`
+	Fields        []FieldDef `json:"fields,omitempty"`
+	Todos         []Todo     `json:"todos"`
+}
+
+type ArchivedTodo struct {
+	Todo
+	ArchivedAt time.Time `json:"archived_at"`
+}
+
+type Archive struct {
+	SchemaVersion int            `json:"schema_version"`
+	Todos         []ArchivedTodo `json:"todos"`
+}
+
+type Settings struct {
+	Backend          string  `json:"backend"`
+	BackupKeep       int     `json:"backup_keep"`
+	BackupDays       int     `json:"backup_days"`
+	DefaultUrgency   int     `json:"default_urgency"`
+	Color            string  `json:"color"`
+	DateFormat       string  `json:"date_format"`
+	ArchiveAfterDays int     `json:"archive_after_days"`
+	Opener           string  `json:"opener"`
+	Hyperlinks       string  `json:"hyperlinks"`
+	ScoreUrgency     float64 `json:"score_urgency"`
+	ScoreAge         float64 `json:"score_age"`
+	ScoreAgeDays     int     `json:"score_age_days"`
+	ScoreDue         float64 `json:"score_due"`
+	ScoreBlocking    float64 `json:"score_blocking"`
+	ScoreBlocked     float64 `json:"score_blocked"`
+	ScoreTags        float64 `json:"score_tags"`
+}
+
+// FieldDef declares a custom field todos can carry. Values lists the
+// choices of an enum field.
+type FieldDef struct {
+	Name   string   `json:"name"`
+	Type   string   `json:"type"`
+	Values []string `json:"values,omitempty"`
 }
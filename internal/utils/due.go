@@ -0,0 +1,220 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	weekdays = map[string]time.Weekday{
+		"sun": time.Sunday, "sunday": time.Sunday,
+		"mon": time.Monday, "monday": time.Monday,
+		"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+		"wed": time.Wednesday, "wednesday": time.Wednesday,
+		"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+		"fri": time.Friday, "friday": time.Friday,
+		"sat": time.Saturday, "saturday": time.Saturday,
+	}
+
+	clockPattern    = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)?$`)
+	relativePattern = regexp.MustCompile(`^(\d+)\s*(m|min|mins|minutes?|h|hours?|d|days?|w|weeks?|mo|months?|y|years?)$`)
+)
+
+// ParseDue turns a due date phrase into a time. It accepts ISO dates
+// ("2026-10-24", "2026-10-24 17:00"), "today", "tomorrow", weekday names
+// ("fri", "next monday"), "in 3 days", "eod", "eow", "eom" and "eoy", each
+// optionally followed by a time of day ("5pm", "17:30", "noon"). In
+// relative phrases "m" means minutes and "mo" months. Dates without a time
+// are returned as local midnight and mean the whole day.
+func ParseDue(input string, now time.Time) (time.Time, error) {
+	trimmed := strings.TrimSpace(input)
+	if trimmed == "" {
+		return time.Time{}, fmt.Errorf("empty due date")
+	}
+
+	// ISO layouts need their uppercase T and Z, so they are tried before
+	// the phrase is lowercased.
+	for _, layout := range []string{"2006-01-02", "2006-01-02 15:04", "2006-01-02T15:04", time.RFC3339} {
+		if t, err := time.ParseInLocation(layout, trimmed, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	phrase := strings.ToLower(trimmed)
+
+	today := StartOfDay(now)
+
+	if match := relativePattern.FindStringSubmatch(strings.TrimPrefix(phrase, "in ")); match != nil {
+		n, _ := strconv.Atoi(match[1])
+		switch match[2][0] {
+		case 'm':
+			if strings.HasPrefix(match[2], "mo") {
+				return today.AddDate(0, n, 0), nil
+			}
+			return now.Add(time.Duration(n) * time.Minute).Truncate(time.Minute), nil
+		case 'h':
+			return now.Add(time.Duration(n) * time.Hour).Truncate(time.Minute), nil
+		case 'd':
+			return today.AddDate(0, 0, n), nil
+		case 'w':
+			return today.AddDate(0, 0, 7*n), nil
+		case 'y':
+			return today.AddDate(n, 0, 0), nil
+		}
+	}
+
+	words := strings.Fields(phrase)
+	hour, minute, hasClock := 0, 0, false
+	if n := len(words); n > 0 {
+		if h, m, ok := parseClock(words[n-1]); ok {
+			hour, minute, hasClock = h, m, true
+			words = words[:n-1]
+			if n := len(words); n > 0 && words[n-1] == "at" {
+				words = words[:n-1]
+			}
+		}
+	}
+
+	day, err := parseDay(strings.Join(words, " "), today)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("cannot understand due date '%s'", input)
+	}
+
+	if hasClock {
+		day = day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	return day, nil
+}
+
+func parseDay(phrase string, today time.Time) (time.Time, error) {
+	switch phrase {
+	case "", "today", "eod":
+		return today, nil
+	case "tomorrow", "tmr":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "eow":
+		return nextWeekday(today, time.Sunday, true), nil
+	case "eom":
+		return time.Date(today.Year(), today.Month()+1, 0, 0, 0, 0, 0, time.Local), nil
+	case "eoy":
+		return time.Date(today.Year(), time.December, 31, 0, 0, 0, 0, time.Local), nil
+	case "next week":
+		return today.AddDate(0, 0, 7), nil
+	case "next month":
+		return today.AddDate(0, 1, 0), nil
+	case "next year":
+		return today.AddDate(1, 0, 0), nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02", phrase, time.Local); err == nil {
+		return t, nil
+	}
+
+	if weekday, ok := weekdays[strings.TrimPrefix(phrase, "next ")]; ok {
+		return nextWeekday(today, weekday, false), nil
+	}
+
+	return time.Time{}, fmt.Errorf("unknown day '%s'", phrase)
+}
+
+// IsWeekday reports whether name is a weekday ParseDue understands, such as
+// "mon" or "Friday".
+func IsWeekday(name string) bool {
+	_, ok := weekdays[strings.ToLower(name)]
+	return ok
+}
+
+// nextWeekday returns the first given weekday after today, or today itself
+// when includeToday is set and it matches.
+func nextWeekday(today time.Time, weekday time.Weekday, includeToday bool) time.Time {
+	days := (int(weekday) - int(today.Weekday()) + 7) % 7
+	if days == 0 && !includeToday {
+		days = 7
+	}
+	return today.AddDate(0, 0, days)
+}
+
+func parseClock(word string) (int, int, bool) {
+	if word == "noon" {
+		return 12, 0, true
+	}
+
+	match := clockPattern.FindStringSubmatch(word)
+	if match == nil || (match[2] == "" && match[3] == "") {
+		return 0, 0, false
+	}
+
+	hour, _ := strconv.Atoi(match[1])
+	minute := 0
+	if match[2] != "" {
+		minute, _ = strconv.Atoi(match[2])
+	}
+
+	switch match[3] {
+	case "am":
+		if hour < 1 || hour > 12 {
+			return 0, 0, false
+		}
+		hour %= 12
+	case "pm":
+		if hour < 1 || hour > 12 {
+			return 0, 0, false
+		}
+		hour = hour%12 + 12
+	}
+
+	if hour > 23 || minute > 59 {
+		return 0, 0, false
+	}
+	return hour, minute, true
+}
+
+func StartOfDay(t time.Time) time.Time {
+	t = t.In(time.Local)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// IsAllDay reports whether a due time was given as a date only.
+func IsAllDay(t time.Time) bool {
+	return StartOfDay(t).Equal(t)
+}
+
+// IsOverdue reports whether due has passed. A date without a time is only
+// overdue once that day is over.
+func IsOverdue(due, now time.Time) bool {
+	if IsAllDay(due) {
+		return StartOfDay(now).After(due)
+	}
+	return now.After(due)
+}
+
+func IsDueToday(due, now time.Time) bool {
+	return StartOfDay(due).Equal(StartOfDay(now))
+}
+
+// FormatDue prints a due time in the user's date format, with the weekday
+// when it is close and the time of day when one was given.
+func FormatDue(due time.Time, format string, now time.Time) string {
+	due = due.In(time.Local)
+
+	text := FormatDate(due, format)
+	switch days := int(math.Round(StartOfDay(due).Sub(StartOfDay(now)).Hours() / 24)); {
+	case days == 0:
+		text = "today"
+	case days == 1:
+		text = "tomorrow"
+	case days > 1 && days < 7:
+		text = due.Format("Mon") + " " + text
+	}
+
+	if !IsAllDay(due) {
+		text += " " + due.Format("15:04")
+	}
+	return text
+}
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDue(t *testing.T) {
+	// Wednesday 14 October 2026, 10:30.
+	now := time.Date(2026, time.October, 14, 10, 30, 0, 0, time.Local)
+	date := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{"2026-10-24", date(time.October, 24, 0, 0)},
+		{"2026-10-24 17:00", date(time.October, 24, 17, 0)},
+		{"2026-10-24T17:00", date(time.October, 24, 17, 0)},
+		{"  2026-10-24T17:00  ", date(time.October, 24, 17, 0)},
+		{"2026-10-24T17:00:00Z", time.Date(2026, time.October, 24, 17, 0, 0, 0, time.UTC)},
+		{"today", date(time.October, 14, 0, 0)},
+		{"Today", date(time.October, 14, 0, 0)},
+		{"tomorrow", date(time.October, 15, 0, 0)},
+		{"tomorrow 5pm", date(time.October, 15, 17, 0)},
+		{"tomorrow at 9:15am", date(time.October, 15, 9, 15)},
+		{"yesterday", date(time.October, 13, 0, 0)},
+		{"fri", date(time.October, 16, 0, 0)},
+		{"wed", date(time.October, 21, 0, 0)},
+		{"next monday noon", date(time.October, 19, 12, 0)},
+		{"eod", date(time.October, 14, 0, 0)},
+		{"eow", date(time.October, 18, 0, 0)},
+		{"eom", date(time.October, 31, 0, 0)},
+		{"eoy", date(time.December, 31, 0, 0)},
+		{"17:30", date(time.October, 14, 17, 30)},
+		{"in 3 days", date(time.October, 17, 0, 0)},
+		{"2w", date(time.October, 28, 0, 0)},
+		{"in 2 hours", date(time.October, 14, 12, 30)},
+		{"45m", date(time.October, 14, 11, 15)},
+		{"1 month", date(time.November, 14, 0, 0)},
+		{"2mo", date(time.December, 14, 0, 0)},
+		{"1y", time.Date(2027, time.October, 14, 0, 0, 0, 0, time.Local)},
+	}
+
+	for _, test := range tests {
+		got, err := ParseDue(test.input, now)
+		if err != nil {
+			t.Errorf("ParseDue(%q): unexpected error: %v", test.input, err)
+			continue
+		}
+		if !got.Equal(test.want) {
+			t.Errorf("ParseDue(%q) = %v, want %v", test.input, got, test.want)
+		}
+	}
+}
+
+func TestParseDueErrors(t *testing.T) {
+	now := time.Date(2026, time.October, 14, 10, 30, 0, 0, time.Local)
+
+	for _, input := range []string{"", "   ", "someday", "13pm", "25:00", "2026-13-01", "next fortnight"} {
+		if got, err := ParseDue(input, now); err == nil {
+			t.Errorf("ParseDue(%q) = %v, want an error", input, got)
+		}
+	}
+}
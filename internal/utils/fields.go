@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dorukozerr/todo-cli/internal/types"
+)
+
+const (
+	FieldString = "string"
+	FieldNumber = "number"
+	FieldDate   = "date"
+	FieldEnum   = "enum"
+)
+
+var (
+	FieldTypes = []string{FieldString, FieldNumber, FieldDate, FieldEnum}
+
+	fieldNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+	// reservedFieldNames are the built-in sort keys of 'todo list', which
+	// a custom field must not shadow.
+	reservedFieldNames = []string{"urgency", "age", "due", "score"}
+)
+
+func FindField(defs []types.FieldDef, name string) (types.FieldDef, bool) {
+	for _, def := range defs {
+		if def.Name == name {
+			return def, true
+		}
+	}
+	return types.FieldDef{}, false
+}
+
+// ValidateFieldDefs checks that custom fields have unique lowercase names,
+// a known type and, for enums, a list of distinct values.
+func ValidateFieldDefs(defs []types.FieldDef) error {
+	seen := make(map[string]bool)
+	for _, def := range defs {
+		if !fieldNamePattern.MatchString(def.Name) {
+			return fmt.Errorf("invalid field name '%s', use lowercase letters, digits and '_'", def.Name)
+		}
+		if slices.Contains(reservedFieldNames, def.Name) {
+			return fmt.Errorf("field name '%s' is reserved", def.Name)
+		}
+		if seen[def.Name] {
+			return fmt.Errorf("field '%s' is declared more than once", def.Name)
+		}
+		seen[def.Name] = true
+
+		if !slices.Contains(FieldTypes, def.Type) {
+			return fmt.Errorf("field '%s': type must be one of %s", def.Name, strings.Join(FieldTypes, ", "))
+		}
+		if def.Type != FieldEnum {
+			if len(def.Values) > 0 {
+				return fmt.Errorf("field '%s': only enum fields have values", def.Name)
+			}
+			continue
+		}
+		if len(def.Values) == 0 {
+			return fmt.Errorf("field '%s': an enum needs at least one value", def.Name)
+		}
+		for i, value := range def.Values {
+			if value == "" || strings.ContainsAny(value, " \t,=") {
+				return fmt.Errorf("field '%s': invalid value '%s'", def.Name, value)
+			}
+			if slices.Contains(def.Values[:i], value) {
+				return fmt.Errorf("field '%s': value '%s' is listed twice", def.Name, value)
+			}
+		}
+	}
+	return nil
+}
+
+// ParseFieldValue checks a value against its field's type and returns it in
+// the form it is stored in: numbers without trailing zeros, dates as
+// YYYY-MM-DD.
+func ParseFieldValue(def types.FieldDef, value string, now time.Time) (string, error) {
+	value = strings.TrimSpace(value)
+	switch def.Type {
+	case FieldNumber:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+			return "", fmt.Errorf("field '%s' takes a number, not '%s'", def.Name, value)
+		}
+		return strconv.FormatFloat(n, 'f', -1, 64), nil
+	case FieldDate:
+		t, err := ParseDue(value, now)
+		if err != nil {
+			return "", fmt.Errorf("field '%s' takes a date: %w", def.Name, err)
+		}
+		return t.Format("2006-01-02"), nil
+	case FieldEnum:
+		if !slices.Contains(def.Values, value) {
+			return "", fmt.Errorf("field '%s' must be one of %s", def.Name, strings.Join(def.Values, ", "))
+		}
+	}
+	return value, nil
+}
+
+// CompareFieldValues orders two stored values of a field: numerically,
+// by date, by the declared order of an enum, or as text.
+func CompareFieldValues(def types.FieldDef, a, b string) int {
+	switch def.Type {
+	case FieldNumber:
+		x, errA := strconv.ParseFloat(a, 64)
+		y, errB := strconv.ParseFloat(b, 64)
+		if errA == nil && errB == nil {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	case FieldEnum:
+		x, y := slices.Index(def.Values, a), slices.Index(def.Values, b)
+		if x >= 0 && y >= 0 {
+			return x - y
+		}
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,201 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	recurDay     = "day"
+	recurWeek    = "week"
+	recurWeekday = "weekday"
+	recurMonth   = "month"
+	recurYear    = "year"
+)
+
+var (
+	intervalPattern = regexp.MustCompile(`^(?:every\s+)?(\d+)\s*(d|days?|w|weeks?|mo|months?|y|years?)$`)
+	monthDayPattern = regexp.MustCompile(`^(?:monthly|every (\d+) months?) on (?:the )?(?:(\d{1,2})(?:st|nd|rd|th)?|(last))$`)
+)
+
+// Recurrence is a parsed repeat rule such as "weekday", "2w" or
+// "monthly on 1st".
+type Recurrence struct {
+	Unit     string
+	Interval int
+	Weekday  *time.Weekday
+	MonthDay int
+}
+
+// ParseRecurrence accepts daily, weekly, monthly, yearly, weekday, a weekday
+// name ("mon"), an interval ("3d", "2w", "6mo", "1y", "every 2 weeks") and
+// a day of the month ("monthly on 1st", "monthly on last", "every 3 months
+// on 15th"). A bare "m" is refused, as due dates read it as minutes.
+func ParseRecurrence(rule string) (Recurrence, error) {
+	phrase := strings.Join(strings.Fields(strings.ToLower(rule)), " ")
+
+	switch phrase {
+	case "daily", "day":
+		return Recurrence{Unit: recurDay, Interval: 1}, nil
+	case "weekly", "week":
+		return Recurrence{Unit: recurWeek, Interval: 1}, nil
+	case "fortnightly", "biweekly":
+		return Recurrence{Unit: recurWeek, Interval: 2}, nil
+	case "weekday", "weekdays":
+		return Recurrence{Unit: recurWeekday, Interval: 1}, nil
+	case "monthly", "month":
+		return Recurrence{Unit: recurMonth, Interval: 1}, nil
+	case "yearly", "annually", "year":
+		return Recurrence{Unit: recurYear, Interval: 1}, nil
+	}
+
+	if weekday, ok := weekdays[strings.TrimPrefix(phrase, "every ")]; ok {
+		return Recurrence{Unit: recurWeek, Interval: 1, Weekday: &weekday}, nil
+	}
+
+	if match := monthDayPattern.FindStringSubmatch(phrase); match != nil {
+		interval := 1
+		if match[1] != "" {
+			interval, _ = strconv.Atoi(match[1])
+			if interval < 1 {
+				return Recurrence{}, fmt.Errorf("repeat interval must be at least 1")
+			}
+		}
+		day := -1
+		if match[3] == "" {
+			day, _ = strconv.Atoi(match[2])
+			if day < 1 || day > 31 {
+				return Recurrence{}, fmt.Errorf("day of month must be between 1 and 31")
+			}
+		}
+		return Recurrence{Unit: recurMonth, Interval: interval, MonthDay: day}, nil
+	}
+
+	if match := intervalPattern.FindStringSubmatch(phrase); match != nil {
+		n, _ := strconv.Atoi(match[1])
+		if n < 1 {
+			return Recurrence{}, fmt.Errorf("repeat interval must be at least 1")
+		}
+		units := map[byte]string{'d': recurDay, 'w': recurWeek, 'm': recurMonth, 'y': recurYear}
+		return Recurrence{Unit: units[match[2][0]], Interval: n}, nil
+	}
+	if strings.HasSuffix(phrase, "m") && intervalPattern.MatchString(phrase+"o") {
+		return Recurrence{}, fmt.Errorf("repeat rule '%s' is ambiguous, use '%so' for months", rule, strings.TrimSpace(rule))
+	}
+
+	return Recurrence{}, fmt.Errorf("cannot understand repeat rule '%s'", rule)
+}
+
+// String gives the canonical form of r, which ParseRecurrence reads back.
+func (r Recurrence) String() string {
+	switch {
+	case r.Unit == recurWeekday:
+		return "weekday"
+	case r.Weekday != nil:
+		return strings.ToLower(r.Weekday.String())
+	case r.MonthDay != 0:
+		day := "last"
+		if r.MonthDay > 0 {
+			day = ordinal(r.MonthDay)
+		}
+		if r.Interval > 1 {
+			return fmt.Sprintf("every %d months on %s", r.Interval, day)
+		}
+		return "monthly on " + day
+	case r.Interval == 1 && r.Unit == recurDay:
+		return "daily"
+	case r.Interval == 1:
+		return r.Unit + "ly"
+	case r.Unit == recurMonth:
+		return fmt.Sprintf("%dmo", r.Interval)
+	}
+	return fmt.Sprintf("%d%c", r.Interval, r.Unit[0])
+}
+
+// Anchored pins a plain month rule to the day of the month of due. Each
+// occurrence is then clamped from that day, so "monthly" from 31 January
+// goes to 28 February and back to 31 March instead of staying on the 28th.
+func (r Recurrence) Anchored(due time.Time) Recurrence {
+	if r.Unit == recurMonth && r.MonthDay == 0 {
+		r.MonthDay = due.In(time.Local).Day()
+	}
+	return r
+}
+
+// Next returns the first occurrence strictly after t, at the same time of
+// day.
+func (r Recurrence) Next(t time.Time) time.Time {
+	t = t.In(time.Local)
+	day := StartOfDay(t)
+	clock := t.Sub(day)
+
+	var next time.Time
+	switch {
+	case r.Unit == recurDay:
+		next = day.AddDate(0, 0, r.Interval)
+	case r.Unit == recurWeekday:
+		next = day.AddDate(0, 0, 1)
+		for next.Weekday() == time.Saturday || next.Weekday() == time.Sunday {
+			next = next.AddDate(0, 0, 1)
+		}
+	case r.Unit == recurWeek && r.Weekday != nil:
+		next = nextWeekday(day, *r.Weekday, false).AddDate(0, 0, 7*(r.Interval-1))
+	case r.Unit == recurWeek:
+		next = day.AddDate(0, 0, 7*r.Interval)
+	case r.Unit == recurMonth && r.MonthDay != 0:
+		next = monthDay(day.Year(), day.Month(), r.MonthDay)
+		if !next.After(day) {
+			next = monthDay(day.Year(), day.Month()+time.Month(r.Interval), r.MonthDay)
+		}
+	case r.Unit == recurMonth:
+		next = addMonths(day, r.Interval)
+	case r.Unit == recurYear:
+		next = addMonths(day, 12*r.Interval)
+	}
+
+	return next.Add(clock)
+}
+
+// First returns the first occurrence on or after today: the next matching
+// day for anchored rules like "mon" or "monthly on 1st", and today for
+// plain intervals.
+func (r Recurrence) First(today time.Time) time.Time {
+	today = StartOfDay(today)
+	if r.Unit == recurWeekday || r.Weekday != nil || r.MonthDay != 0 {
+		return r.Next(today.AddDate(0, 0, -1))
+	}
+	return today
+}
+
+// monthDay returns the given day of a month, clamped to the month's last
+// day; -1 means the last day.
+func monthDay(year int, month time.Month, day int) time.Time {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.Local)
+	if day == -1 || day > last.Day() {
+		return last
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+}
+
+// addMonths moves t by n months, clamping to the end of shorter months
+// instead of overflowing into the next one.
+func addMonths(t time.Time, n int) time.Time {
+	return monthDay(t.Year(), t.Month()+time.Month(n), t.Day())
+}
+
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return strconv.Itoa(n) + suffix
+}
@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	tests := []struct {
+		rule string
+		want string
+	}{
+		{"daily", "daily"},
+		{"Weekly", "weekly"},
+		{"fortnightly", "2w"},
+		{"weekdays", "weekday"},
+		{"monthly", "monthly"},
+		{"annually", "yearly"},
+		{"mon", "monday"},
+		{"every friday", "friday"},
+		{"3d", "3d"},
+		{"every 2 weeks", "2w"},
+		{"6mo", "6mo"},
+		{"6 months", "6mo"},
+		{"1y", "yearly"},
+		{"monthly on 1st", "monthly on 1st"},
+		{"monthly on the 22nd", "monthly on 22nd"},
+		{"monthly on last", "monthly on last"},
+		{"every 3 months on 15th", "every 3 months on 15th"},
+		{"every 2 months on last", "every 2 months on last"},
+	}
+
+	for _, test := range tests {
+		rule, err := ParseRecurrence(test.rule)
+		if err != nil {
+			t.Errorf("ParseRecurrence(%q): unexpected error: %v", test.rule, err)
+			continue
+		}
+		if got := rule.String(); got != test.want {
+			t.Errorf("ParseRecurrence(%q).String() = %q, want %q", test.rule, got, test.want)
+		}
+		if again, err := ParseRecurrence(rule.String()); err != nil || again.String() != rule.String() {
+			t.Errorf("ParseRecurrence(%q) does not read back %q", rule.String(), test.rule)
+		}
+	}
+}
+
+func TestParseRecurrenceErrors(t *testing.T) {
+	for _, rule := range []string{"", "sometimes", "0d", "2m", "every 6 m", "monthly on 32nd", "monthly on 0th", "every 0 months on 1st"} {
+		if got, err := ParseRecurrence(rule); err == nil {
+			t.Errorf("ParseRecurrence(%q) = %v, want an error", rule, got)
+		}
+	}
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+	}
+	parse := func(rule string) Recurrence {
+		r, err := ParseRecurrence(rule)
+		if err != nil {
+			t.Fatalf("ParseRecurrence(%q): %v", rule, err)
+		}
+		return r
+	}
+
+	tests := []struct {
+		name  string
+		rule  Recurrence
+		start time.Time
+		want  []time.Time
+	}{
+		{
+			name:  "anchored monthly keeps the 31st",
+			rule:  parse("monthly").Anchored(date(2026, time.January, 31)),
+			start: date(2026, time.January, 31),
+			want:  []time.Time{date(2026, time.February, 28), date(2026, time.March, 31), date(2026, time.April, 30)},
+		},
+		{
+			name:  "anchored interval",
+			rule:  parse("2mo").Anchored(date(2026, time.August, 31)),
+			start: date(2026, time.August, 31),
+			want:  []time.Time{date(2026, time.October, 31), date(2026, time.December, 31), date(2027, time.February, 28)},
+		},
+		{
+			name:  "last day of the month",
+			rule:  parse("monthly on last"),
+			start: date(2028, time.January, 31),
+			want:  []time.Time{date(2028, time.February, 29), date(2028, time.March, 31)},
+		},
+		{
+			name:  "weekday skips the weekend",
+			rule:  parse("weekday"),
+			start: date(2026, time.October, 16),
+			want:  []time.Time{date(2026, time.October, 19), date(2026, time.October, 20)},
+		},
+		{
+			name:  "day name",
+			rule:  parse("mon"),
+			start: date(2026, time.October, 14),
+			want:  []time.Time{date(2026, time.October, 19), date(2026, time.October, 26)},
+		},
+		{
+			name:  "keeps the time of day",
+			rule:  parse("3d"),
+			start: date(2026, time.October, 14).Add(9 * time.Hour),
+			want:  []time.Time{date(2026, time.October, 17).Add(9 * time.Hour), date(2026, time.October, 20).Add(9 * time.Hour)},
+		},
+	}
+
+	for _, test := range tests {
+		current := test.start
+		for i, want := range test.want {
+			current = test.rule.Next(current)
+			if !current.Equal(want) {
+				t.Errorf("%s: occurrence %d = %v, want %v", test.name, i+1, current, want)
+				break
+			}
+		}
+	}
+}
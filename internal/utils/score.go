@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dorukozerr/todo-cli/internal/types"
+)
+
+// ScoreTerm is one part of a todo's score: a factor, the urgency level or a
+// number between 0 and 1, weighted by a coefficient from the settings.
+type ScoreTerm struct {
+	Name        string
+	Reason      string
+	Factor      float64
+	Coefficient float64
+}
+
+func (t ScoreTerm) Value() float64 {
+	return t.Factor * t.Coefficient
+}
+
+// ScoreTerms breaks down the score of an open todo. Urgency and age always
+// count; due date, dependencies and tags only when the todo has them.
+// blocked and blocking tell whether the todo waits for an open todo and
+// whether an open todo waits for it, which only the whole store knows.
+func ScoreTerms(todo types.Todo, s *types.Settings, blocked, blocking bool, now time.Time) []ScoreTerm {
+	terms := []ScoreTerm{{
+		Name:        "urgency",
+		Reason:      fmt.Sprintf("level %d", todo.Urgency),
+		Factor:      float64(todo.Urgency),
+		Coefficient: s.ScoreUrgency,
+	}}
+
+	days := max(now.Sub(todo.CreatedAt).Hours()/24, 0)
+	ageDays := float64(max(s.ScoreAgeDays, 1))
+	terms = append(terms, ScoreTerm{
+		Name:        "age",
+		Reason:      fmt.Sprintf("%.0f of %d days", min(days, ageDays), s.ScoreAgeDays),
+		Factor:      min(days/ageDays, 1),
+		Coefficient: s.ScoreAge,
+	})
+
+	if todo.Due != nil {
+		terms = append(terms, ScoreTerm{
+			Name:        "due",
+			Reason:      "due " + FormatDue(*todo.Due, s.DateFormat, now),
+			Factor:      dueFactor(*todo.Due, now),
+			Coefficient: s.ScoreDue,
+		})
+	}
+	if blocking {
+		terms = append(terms, ScoreTerm{Name: "blocking", Reason: "open todos wait for it", Factor: 1, Coefficient: s.ScoreBlocking})
+	}
+	if blocked {
+		terms = append(terms, ScoreTerm{Name: "blocked", Reason: "waits for open todos", Factor: 1, Coefficient: s.ScoreBlocked})
+	}
+	if n := len(todo.Tags); n > 0 {
+		terms = append(terms, ScoreTerm{
+			Name:        "tags",
+			Reason:      fmt.Sprintf("%d tag(s)", n),
+			Factor:      min(0.7+0.1*float64(n), 1),
+			Coefficient: s.ScoreTags,
+		})
+	}
+
+	return terms
+}
+
+func Score(terms []ScoreTerm) float64 {
+	score := 0.0
+	for _, term := range terms {
+		score += term.Value()
+	}
+	return score
+}
+
+// dueFactor grows from 0.2 two weeks before the due date to 1 a week after
+// it, so a todo becomes more pressing as its due date comes closer.
+func dueFactor(due, now time.Time) float64 {
+	overdue := now.Sub(due).Hours() / 24
+	switch {
+	case overdue >= 7:
+		return 1
+	case overdue >= -14:
+		return 0.2 + (overdue+14)*0.8/21
+	}
+	return 0.2
+}
@@ -3,38 +3,36 @@ package utils
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/dorukozerr/todo-cli/internal/config"
 	"github.com/dorukozerr/todo-cli/internal/types"
 )
 
-func GenerateRandomID() (string, error) {
+// GenerateUUID returns a random version 4 UUID.
+func GenerateUUID() string {
 	bytes := make([]byte, 16)
 
-	_, err := rand.Read(bytes)
-	if err != nil {
-		return "", err
-	}
+	// crypto/rand.Read never fails, it crashes the program instead.
+	_, _ = rand.Read(bytes)
+	bytes[6] = bytes[6]&0x0f | 0x40
+	bytes[8] = bytes[8]&0x3f | 0x80
 
-	return hex.EncodeToString(bytes), nil
+	id := hex.EncodeToString(bytes)
+	return id[0:8] + "-" + id[8:12] + "-" + id[12:16] + "-" + id[16:20] + "-" + id[20:32]
 }
 
-func GenerateNextTodoID(c types.Config) string {
-	if len(c.Todos) == 0 {
-		return "1"
-	}
-
-	maxID := 0
+// NextTodoNumber is the number a new todo gets. Numbers only grow, so the
+// number of a deleted todo is not given to another one.
+func NextTodoNumber(c types.Config) int {
+	next := max(c.NextNumber, 1)
 	for _, todo := range c.Todos {
-		if id, err := strconv.Atoi(todo.ID); err == nil {
-			if id > maxID {
-				maxID = id
-			}
-		}
+		next = max(next, todo.Number+1)
 	}
-
-	return strconv.Itoa(maxID + 1)
+	return next
 }
 
 func CountTodosInGroup(c types.Config, groupName string) int {
@@ -65,3 +63,67 @@ func GetUrgencyDisplay(urgency int) (string, string) {
 		return "UNKNOWN", config.White
 	}
 }
+
+func FormatDate(t time.Time, format string) string {
+	switch format {
+	case "us":
+		return t.Format("01/02/2006")
+	case "eu":
+		return t.Format("02.01.2006")
+	default:
+		return t.Format("2006-01-02")
+	}
+}
+
+func FormatDateTime(t time.Time, format string) string {
+	return FormatDate(t, format) + " " + t.Format("15:04")
+}
+
+// FormatAge describes how long before now t was, e.g. "3d ago".
+func FormatAge(t, now time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case d < 14*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	case d < 60*24*time.Hour:
+		return fmt.Sprintf("%dw ago", int(d.Hours()/24/7))
+	case d < 365*24*time.Hour:
+		return fmt.Sprintf("%dmo ago", int(d.Hours()/24/30))
+	default:
+		return fmt.Sprintf("%dy ago", int(d.Hours()/24/365))
+	}
+}
+
+// FormatDuration prints a duration as hours and minutes, e.g. "2h 05m".
+func FormatDuration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh %02dm", minutes/60, minutes%60)
+}
+
+// ParseDuration extends time.ParseDuration with days ("30d") and weeks ("2w").
+func ParseDuration(value string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if number, ok := strings.CutSuffix(value, suffix); ok {
+			n, err := strconv.Atoi(number)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid duration '%s'", value)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration '%s'", value)
+	}
+	return d, nil
+}
@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dorukozerr/todo-cli/internal/types"
+)
+
+const (
+	StatusTodo       = "todo"
+	StatusInProgress = "in_progress"
+	StatusWaiting    = "waiting"
+	StatusBlocked    = "blocked"
+	StatusDone       = "done"
+	StatusCancelled  = "cancelled"
+)
+
+// DefaultWorkflow is used by stores that do not define their own. Open
+// states may move anywhere; closed ones can only be reopened.
+func DefaultWorkflow() types.Workflow {
+	return types.Workflow{
+		States: []types.State{
+			{Name: StatusInProgress, Symbol: "~"},
+			{Name: StatusTodo, Symbol: " "},
+			{Name: StatusWaiting, Symbol: "?"},
+			{Name: StatusBlocked, Symbol: "!"},
+			{Name: StatusDone, Closed: true, Symbol: "x"},
+			{Name: StatusCancelled, Closed: true, Symbol: "-"},
+		},
+		Initial: StatusTodo,
+		Done:    StatusDone,
+		Transitions: map[string][]string{
+			StatusDone:      {StatusTodo},
+			StatusCancelled: {StatusTodo},
+		},
+	}
+}
+
+// StoreWorkflow returns the workflow of a store, or the default one.
+func StoreWorkflow(c *types.Config) types.Workflow {
+	if c.Workflow == nil {
+		return DefaultWorkflow()
+	}
+	return *c.Workflow
+}
+
+func FindState(w types.Workflow, name string) (types.State, bool) {
+	for _, state := range w.States {
+		if state.Name == name {
+			return state, true
+		}
+	}
+	return types.State{}, false
+}
+
+// StateRank is the position of a state in the workflow, which is the order
+// listings sort by. Unknown states go last.
+func StateRank(w types.Workflow, name string) int {
+	for i, state := range w.States {
+		if state.Name == name {
+			return i
+		}
+	}
+	return len(w.States)
+}
+
+// CanTransition reports whether a todo may move from one state to another.
+func CanTransition(w types.Workflow, from, to string) bool {
+	allowed, ok := w.Transitions[from]
+	return !ok || slices.Contains(allowed, to)
+}
+
+func StateSymbol(state types.State) string {
+	if state.Symbol != "" {
+		return state.Symbol
+	}
+	return state.Name[:1]
+}
+
+// NormalizeStatus lets states be typed as "In-Progress" or "in progress".
+func NormalizeStatus(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.NewReplacer("-", "_", " ", "_").Replace(name)
+}
+
+// ValidateWorkflow checks that a workflow is usable: state names are unique
+// words, Initial is open, Done is closed and transitions only mention known
+// states.
+func ValidateWorkflow(w types.Workflow) error {
+	if len(w.States) == 0 {
+		return fmt.Errorf("workflow has no states")
+	}
+
+	seen := make(map[string]bool)
+	for _, state := range w.States {
+		if state.Name == "" || state.Name != NormalizeStatus(state.Name) {
+			return fmt.Errorf("invalid state name '%s', use lowercase words joined by '_'", state.Name)
+		}
+		if seen[state.Name] {
+			return fmt.Errorf("state '%s' is defined more than once", state.Name)
+		}
+		if len([]rune(state.Symbol)) > 1 {
+			return fmt.Errorf("state '%s': symbol must be a single character", state.Name)
+		}
+		seen[state.Name] = true
+	}
+
+	if initial, ok := FindState(w, w.Initial); !ok || initial.Closed {
+		return fmt.Errorf("initial state '%s' must be an open state of the workflow", w.Initial)
+	}
+	if done, ok := FindState(w, w.Done); !ok || !done.Closed {
+		return fmt.Errorf("done state '%s' must be a closed state of the workflow", w.Done)
+	}
+
+	for from, targets := range w.Transitions {
+		if !seen[from] {
+			return fmt.Errorf("transitions: unknown state '%s'", from)
+		}
+		for _, to := range targets {
+			if !seen[to] {
+				return fmt.Errorf("transitions from '%s': unknown state '%s'", from, to)
+			}
+		}
+	}
+
+	return nil
+}